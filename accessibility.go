@@ -0,0 +1,86 @@
+// Use of this source code is subject to an MIT-style
+// licence which can be found in the LICENSE file.
+
+package main
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// colorBlindMode picks a palette that's easier to tell apart for colour-blind
+// players, one of: off, protanopia, deuteranopia, tritanopia
+var colorBlindMode string = "off"
+
+// reducedMotion turns off camera shake and recoil, the zoom animation and
+// flashing text for players sensitive to motion
+var reducedMotion bool = false
+
+// toggleHoldActions makes actions you normally have to hold down, like
+// sprinting and aiming, switch on and off with each press instead
+var toggleHoldActions bool = false
+
+// Palette is the set of colours used to give feedback that colour-blind
+// players might not be able to tell apart
+type Palette struct {
+	Hit    color.RGBA // Cursor marker when a shot hits
+	Death  color.RGBA // Text on the death screen
+	Danger color.RGBA // Warnings on the HUD and minimap
+}
+
+// palettes are the palettes for each colour-blind mode, the alternatives are
+// picked from the Okabe-Ito colour-blind safe palette
+var palettes = map[string]Palette{
+	"off": {
+		Hit:    color.RGBA{0xff, 0x00, 0x00, 0xff},
+		Death:  color.RGBA{0xc8, 0x44, 0x13, 0xff},
+		Danger: color.RGBA{0xe0, 0x40, 0x30, 0xff},
+	},
+	// Red and green look alike, so use orange and blue instead
+	"protanopia": {
+		Hit:    color.RGBA{0x56, 0xb4, 0xe9, 0xff},
+		Death:  color.RGBA{0xe6, 0x9f, 0x00, 0xff},
+		Danger: color.RGBA{0xe6, 0x9f, 0x00, 0xff},
+	},
+	"deuteranopia": {
+		Hit:    color.RGBA{0x56, 0xb4, 0xe9, 0xff},
+		Death:  color.RGBA{0xe6, 0x9f, 0x00, 0xff},
+		Danger: color.RGBA{0xe6, 0x9f, 0x00, 0xff},
+	},
+	// Blue and yellow look alike, red stays clear
+	"tritanopia": {
+		Hit:    color.RGBA{0xd5, 0x5e, 0x00, 0xff},
+		Death:  color.RGBA{0xcc, 0x79, 0xa7, 0xff},
+		Danger: color.RGBA{0xd5, 0x5e, 0x00, 0xff},
+	},
+}
+
+// palette returns the palette for the chosen colour-blind mode
+func palette() Palette {
+	if p, ok := palettes[colorBlindMode]; ok {
+		return p
+	}
+	return palettes["off"]
+}
+
+// recolor replaces every pixel of one colour in an image with another colour,
+// used to swap the red in sprites for the palette's colour
+func recolor(img image.Image, from, to color.RGBA) *ebiten.Image {
+	if from == to {
+		return ebiten.NewImageFromImage(img)
+	}
+	b := img.Bounds()
+	out := image.NewRGBA(b)
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			c := color.RGBAModel.Convert(img.At(x, y)).(color.RGBA)
+			if c == from {
+				c = to
+			}
+			out.SetRGBA(x, y, c)
+		}
+	}
+	return ebiten.NewImageFromImage(out)
+}
@@ -49,6 +49,12 @@ func (z *Boss) Draw(g *GameScreen) {
 	z.Zombie.Draw(g)
 }
 
+// Corpse returns what remains of the boss after its final death
+func (z *Boss) Corpse() *Corpse {
+	z.Zombie.Frame = z.Frame
+	return z.Zombie.Corpse()
+}
+
 // State changes triggered by LAST frame of animation
 func (z *Boss) animationEndTriggers(g *GameScreen) {
 	switch z.State {
@@ -68,6 +74,7 @@ func (z *Boss) animationEndTriggers(g *GameScreen) {
 		z.State = bossRunning
 		z.Zombie.State = zombieWalking
 		g.Sounds[soundBigZombieScream].Play()
+		g.CameraEffects.AddTrauma(traumaBossScream)
 	case bossDeath2:
 		z.Die(g)
 		z.Zombie.State = zombieDead
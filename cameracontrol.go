@@ -0,0 +1,128 @@
+// Use of this source code is subject to an MIT-style
+// licence which can be found in the LICENSE file.
+
+package main
+
+import (
+	"math"
+)
+
+// How long (ticks) it roughly takes the camera to catch up with its target
+const cameraSmoothTime = 10.0
+
+// How far towards the cursor the camera looks ahead, as a fraction of the
+// cursor's distance from the middle of the screen, when zoomed out and in
+const (
+	cameraLookAhead       = 0.2
+	cameraLookAheadZoomed = 0.5
+)
+
+// How close (pixels) the player and dog may get to the edge of the screen when
+// the camera is framing both of them
+const cameraFramingMargin = 32.0
+
+// How far the camera may zoom out to fit both the player and the dog on the
+// screen, if they're further apart than this the dog goes off-screen
+const cameraFramingMinZoom = 0.6
+
+// CameraMode is how the camera decides what to look at
+type CameraMode uint8
+
+const (
+	cameraFollowPlayer      CameraMode = iota // Follow the player, looking ahead towards the cursor
+	cameraFramePlayerAndDog                   // Keep both the player and the dog on the screen
+)
+
+// CameraController smoothly moves the camera towards what it should be looking
+// at, instead of snapping there every tick
+type CameraController struct {
+	Mode         CameraMode // What the camera is looking at
+	Position     Coord      // Where the camera is looking now
+	Velocity     Coord      // How fast the camera is moving
+	Zoom         float64    // How far the camera zoomed out to fit everything in, 1 is not at all
+	ZoomVelocity float64    // How fast the camera is zooming
+}
+
+// NewCameraController creates a camera controller following the player
+func NewCameraController() *CameraController {
+	return &CameraController{Mode: cameraFollowPlayer, Zoom: 1}
+}
+
+// Snap moves the camera to the position immediately, e.g. when respawning
+func (cc *CameraController) Snap(position Coord) {
+	cc.Position = position
+	cc.Velocity = Coord{}
+}
+
+// ToggleMode switches between following the player and framing the player
+// together with the dog
+func (cc *CameraController) ToggleMode() {
+	if cc.Mode == cameraFollowPlayer {
+		cc.Mode = cameraFramePlayerAndDog
+	} else {
+		cc.Mode = cameraFollowPlayer
+	}
+}
+
+// Update moves the camera one tick closer to its target
+func (cc *CameraController) Update(g *GameScreen) {
+	target := cc.target(g)
+	cc.Position.X = smoothDamp(cc.Position.X, target.X, &cc.Velocity.X, cameraSmoothTime)
+	cc.Position.Y = smoothDamp(cc.Position.Y, target.Y, &cc.Velocity.Y, cameraSmoothTime)
+	cc.Zoom = smoothDamp(cc.Zoom, cc.targetZoom(g), &cc.ZoomVelocity, cameraSmoothTime)
+}
+
+// targetZoom calculates how far the camera has to zoom out to fit the player
+// and the dog on the screen when it's framing both of them
+func (cc *CameraController) targetZoom(g *GameScreen) float64 {
+	if cc.Mode != cameraFramePlayerAndDog {
+		return 1
+	}
+	player, dog := g.Player.Position(), g.Dog.Position()
+	w := math.Abs(player.X-dog.X) + cameraFramingMargin*2
+	h := math.Abs(player.Y-dog.Y) + cameraFramingMargin*2
+	zoom := math.Min(float64(g.Width)/w, float64(g.Height)/h)
+	return math.Max(cameraFramingMinZoom, math.Min(1, zoom))
+}
+
+// target calculates where the camera should be looking in its current mode
+func (cc *CameraController) target(g *GameScreen) Coord {
+	player := g.Player.Position()
+
+	switch cc.Mode {
+	case cameraFramePlayerAndDog:
+		dog := g.Dog.Position()
+		// Aim between them, the camera zooms out to fit them both in, but if
+		// they're too far apart never go so far that the player is off-screen
+		w := float64(g.Width)/g.Camera.Scale/2 - cameraFramingMargin
+		h := float64(g.Height)/g.Camera.Scale/2 - cameraFramingMargin
+		return Coord{
+			X: math.Min(math.Max((player.X+dog.X)/2, player.X-w), player.X+w),
+			Y: math.Min(math.Max((player.Y+dog.Y)/2, player.Y-h), player.Y+h),
+		}
+	default:
+		// Look further ahead the more the camera has zoomed in
+		zoomed := (g.Zoom.Amount - zoomOutLevel) / (zoomInLevel - zoomOutLevel)
+		lookAhead := cameraLookAhead + (cameraLookAheadZoomed-cameraLookAhead)*zoomed
+		// Measured from the camera rather than the player so that looking
+		// ahead doesn't push the cursor further away and run off
+		cx, cy := g.Camera.GetWorldCoords(cursorPosition())
+		return Coord{
+			X: player.X + (cx-g.Camera.X)*lookAhead,
+			Y: player.Y + (cy-g.Camera.Y)*lookAhead,
+		}
+	}
+}
+
+// smoothDamp moves current towards target like a critically damped spring,
+// so that it eases in and out without overshooting, smoothTime is roughly how
+// many ticks it takes to get there
+func smoothDamp(current, target float64, velocity *float64, smoothTime float64) float64 {
+	omega := 2 / smoothTime
+	x := omega
+	exp := 1 / (1 + x + 0.48*x*x + 0.235*x*x*x)
+	change := current - target
+	temp := *velocity + omega*change
+	*velocity = (*velocity - omega*temp) * exp
+	return target + (change+temp)*exp
+}
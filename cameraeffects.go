@@ -0,0 +1,99 @@
+// Use of this source code is subject to an MIT-style
+// licence which can be found in the LICENSE file.
+
+package main
+
+import (
+	"math"
+	"math/rand"
+)
+
+// The furthest (pixels) the camera is moved away when shaking at full trauma
+const shakeMaxOffset = 6.0
+
+// How much trauma wears off every tick
+const traumaDecay = 0.025
+
+// How far (pixels) the camera is kicked by the recoil of a gunshot
+const recoilDistance = 3.0
+
+// How much of the recoil kick is left after each tick
+const recoilDamping = 0.7
+
+// Amounts of trauma caused by different events, 1 being the most severe
+const (
+	traumaGunShot    = 0.3
+	traumaBossScream = 0.8
+	traumaPlayerDies = 1.0
+)
+
+// How long (ticks) the action is frozen for when the boss is hit
+const bossHitStopTime = 4
+
+// How long (ticks) the screen is frozen and shaking before showing the death
+// screen when the player dies
+const deathShakeTime = 40
+
+// CameraEffects adds feedback effects on top of the camera's position, like
+// shaking, recoil kicks and short pauses in the action (hit-stop)
+type CameraEffects struct {
+	Trauma  float64 // How strongly the camera is shaking, from 0 to 1
+	Recoil  Coord   // Offset of the camera due to recoil
+	HitStop int     // How many more ticks the action is frozen for
+	offset  Coord   // Combined offset of all effects for the current tick
+}
+
+// NewCameraEffects creates a camera effects tracker with no effects playing
+func NewCameraEffects() *CameraEffects {
+	return &CameraEffects{}
+}
+
+// AddTrauma makes the camera shake more, trauma is capped at 1
+func (ce *CameraEffects) AddTrauma(amount float64) {
+	ce.Trauma = math.Min(1, ce.Trauma+amount)
+}
+
+// Kick offsets the camera in the direction of the angle, e.g. from a gunshot
+func (ce *CameraEffects) Kick(angle float64) {
+	ce.Recoil.X += math.Cos(angle) * recoilDistance
+	ce.Recoil.Y += math.Sin(angle) * recoilDistance
+}
+
+// Stop freezes the action for the given number of ticks
+func (ce *CameraEffects) Stop(ticks int) {
+	if ticks > ce.HitStop {
+		ce.HitStop = ticks
+	}
+}
+
+// Stopped returns whether the action is currently frozen
+func (ce *CameraEffects) Stopped() bool {
+	return ce.HitStop > 0
+}
+
+// Update wears off the effects and calculates the camera offset for this tick
+func (ce *CameraEffects) Update() {
+	if ce.HitStop > 0 {
+		ce.HitStop--
+	}
+
+	ce.Recoil.X *= recoilDamping
+	ce.Recoil.Y *= recoilDamping
+
+	// Shaking grows with the square of trauma, so small amounts barely shake
+	shake := ce.Trauma * ce.Trauma * shakeMaxOffset
+	ce.Trauma = math.Max(0, ce.Trauma-traumaDecay)
+
+	ce.offset = Coord{
+		X: ce.Recoil.X + (rand.Float64()*2-1)*shake,
+		Y: ce.Recoil.Y + (rand.Float64()*2-1)*shake,
+	}
+	if reducedMotion {
+		ce.offset = Coord{}
+	}
+}
+
+// Offset returns how far the camera should be moved away from its target
+func (ce *CameraEffects) Offset() (float64, float64) {
+	return ce.offset.X, ce.offset.Y
+}
@@ -0,0 +1,193 @@
+// Use of this source code is subject to an MIT-style
+// licence which can be found in the LICENSE file.
+
+package main
+
+import (
+	"image"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	camera "github.com/melonfunction/ebiten-camera"
+	"github.com/solarlune/resolv"
+)
+
+// chunkSize is the width and height (pixels) of each pre-rendered chunk of the
+// map, it's kept small enough to fit within texture limits on any device
+const chunkSize = 512
+
+// How long (frames) a chunk can go without being drawn before it's disposed
+const chunkDisposeTime = 2 * 60
+
+// How far (pixels) around the player and dog fading layers fade out and how
+// see-through they are right above them
+const (
+	fadeRadius   = 56
+	fadeMinAlpha = 0.35
+)
+
+// ChunkRenderer draws the part of the map within bounds (world coordinates) to
+// a chunk image whose top-left corner is at bounds.Min
+type ChunkRenderer func(chunk *ebiten.Image, bounds image.Rectangle)
+
+// chunk is a pre-rendered piece of a ChunkedImage
+type chunk struct {
+	Image     *ebiten.Image
+	LastDrawn int // Frame the chunk was last drawn on
+}
+
+// ChunkedImage is a big image of the whole map split into chunks, only the
+// chunks that are on the screen are rendered, and the ones that go off the
+// screen for a while are thrown away to save memory
+type ChunkedImage struct {
+	Bounds   image.Rectangle // Area of the map the image covers
+	Render   ChunkRenderer   // Renders each chunk when it first comes on screen
+	Animated *RenderedLayer  // Layer whose animated tiles are drawn over the chunks, nil for none
+	Parallax Coord           // LDtk parallax factors, 0 for the ground, less than 0 looks higher up, never scaled
+	Fade     *ebiten.Shader  // Shader fading it out around the player and dog, nil to not fade
+	chunks   map[image.Point]*chunk
+	frame    int
+}
+
+// NewChunkedImage creates a chunked image of the given size with no chunks
+// rendered yet
+func NewChunkedImage(width, height int, render ChunkRenderer) *ChunkedImage {
+	return &ChunkedImage{
+		Bounds: image.Rect(0, 0, width, height),
+		Render: render,
+		chunks: map[image.Point]*chunk{},
+	}
+}
+
+// Draw draws the chunks overlapping the camera to its surface, rendering any
+// that are missing and disposing of ones that haven't been needed for a while
+func (ci *ChunkedImage) Draw(g *GameScreen) {
+	ci.frame++
+
+	// Layers are moved along with the camera by their parallax factors, so
+	// negative factors scroll faster than the ground like LDtk shows them.
+	// Like in LDtk they're where they were painted when the camera is on the
+	// middle of the level and drift further away the further it is from it.
+	middle := ci.Bounds.Min.Add(ci.Bounds.Max).Div(2)
+	shift := Coord{
+		X: (g.Camera.X - float64(middle.X)) * ci.Parallax.X,
+		Y: (g.Camera.Y - float64(middle.Y)) * ci.Parallax.Y,
+	}
+	view := cameraView(g.Camera).
+		Sub(image.Pt(int(shift.X), int(shift.Y))).
+		Intersect(ci.Bounds)
+
+	// Where the player and dog are as seen on this layer
+	var centers []float32
+	for _, d := range []*resolv.Object{g.Player.Object, g.Dog.Object} {
+		centers = append(centers,
+			float32(d.Position.X-shift.X),
+			float32(d.Position.Y-shift.Y),
+		)
+	}
+
+	for y := floorDiv(view.Min.Y, chunkSize); y*chunkSize < view.Max.Y; y++ {
+		for x := floorDiv(view.Min.X, chunkSize); x*chunkSize < view.Max.X; x++ {
+			c := ci.chunk(image.Pt(x, y))
+			c.LastDrawn = ci.frame
+			op := &ebiten.DrawImageOptions{}
+			g.Camera.GetTranslation(op, float64(x*chunkSize)+shift.X, float64(y*chunkSize)+shift.Y)
+			ci.drawImage(g, c.Image, op, image.Pt(x*chunkSize, y*chunkSize), centers)
+		}
+	}
+
+	// Animated tiles go right on top of their own layer so that the layers
+	// above still cover them
+	if ci.Animated != nil {
+		rl := ci.Animated
+		size := image.Pt(rl.Layer.GridSize, rl.Layer.GridSize)
+		for _, at := range rl.Animated {
+			if !(image.Rectangle{at.Position, at.Position.Add(size)}).Overlaps(view) {
+				continue
+			}
+			tile := rl.Tileset.SubImage(tileSrc(rl.Layer.Tileset, at.Frame(g.Tick))).(*ebiten.Image)
+			op := tileOptions(at.Tile, rl.Layer.GridSize)
+			g.Camera.GetTranslation(op, float64(at.Position.X)+shift.X, float64(at.Position.Y)+shift.Y)
+			ci.drawImage(g, tile, op, at.Position, centers)
+		}
+	}
+
+	for key, c := range ci.chunks {
+		if ci.frame-c.LastDrawn > chunkDisposeTime {
+			c.Image.Dispose()
+			delete(ci.chunks, key)
+		}
+	}
+}
+
+// drawImage draws a chunk or tile whose top-left corner is at origin on the
+// layer to the camera surface, faded out around the player and dog if the
+// image fades
+func (ci *ChunkedImage) drawImage(g *GameScreen, img *ebiten.Image, op *ebiten.DrawImageOptions, origin image.Point, centers []float32) {
+	if ci.Fade == nil {
+		g.Camera.Surface.DrawImage(img, op)
+		return
+	}
+	size := img.Bounds().Size()
+	g.Camera.Surface.DrawRectShader(size.X, size.Y, ci.Fade, &ebiten.DrawRectShaderOptions{
+		GeoM:   op.GeoM,
+		Images: [4]*ebiten.Image{img},
+		Uniforms: map[string]any{
+			"Origin":   []float32{float32(origin.X), float32(origin.Y)},
+			"Centers":  centers,
+			"Radius":   float32(fadeRadius),
+			"MinAlpha": float32(fadeMinAlpha),
+		},
+	})
+}
+
+// chunk returns the chunk at the given chunk coordinates, rendering it first
+// if it isn't already
+func (ci *ChunkedImage) chunk(key image.Point) *chunk {
+	if c, ok := ci.chunks[key]; ok {
+		return c
+	}
+	bounds := image.Rect(0, 0, chunkSize, chunkSize).Add(key.Mul(chunkSize))
+	img := ebiten.NewImage(chunkSize, chunkSize)
+	ci.Render(img, bounds)
+	c := &chunk{Image: img}
+	ci.chunks[key] = c
+	return c
+}
+
+// Dispose throws away all the rendered chunks, they are rendered again when
+// they are next drawn
+func (ci *ChunkedImage) Dispose() {
+	for key, c := range ci.chunks {
+		c.Image.Dispose()
+		delete(ci.chunks, key)
+	}
+}
+
+// cameraView returns the area of the map the camera can see
+func cameraView(cam *camera.Camera) image.Rectangle {
+	w, h := cam.Surface.Size()
+	return image.Rect(
+		int(cam.X)-w/2, int(cam.Y)-h/2,
+		int(cam.X)+w/2+1, int(cam.Y)+h/2+1,
+	)
+}
+
+// drawInChunk draws img to a chunk image as if it were at x, y on the map
+func drawInChunk(chunk, img *ebiten.Image, bounds image.Rectangle, x, y float64, op *ebiten.DrawImageOptions) {
+	op.GeoM.Translate(x-float64(bounds.Min.X), y-float64(bounds.Min.Y))
+	chunk.DrawImage(img, op)
+}
+
+// floorDiv divides rounding down, also for negative numbers
+func floorDiv(a, b int) int {
+	if a < 0 {
+		return -((-a + b - 1) / b)
+	}
+	return a / b
+}
+
+// MapDecal is an image drawn onto the map's background, like checkpoint signs
+type MapDecal struct {
+	Image    *ebiten.Image
+	Position Coord // Top-left corner of the image on the map
+}
@@ -26,6 +26,16 @@ func ApplyConfigs() {
 	if err != nil {
 		log.Println("Error parsing INI file:", err)
 	}
+	lightingEnabled = cfg.Section("").Key("LightingEnabled").MustBool(lightingEnabled)
+	fogOfWarEnabled = cfg.Section("").Key("FogOfWarEnabled").MustBool(fogOfWarEnabled)
+	threatIndicatorsEnabled = cfg.Section("").Key("ThreatIndicatorsEnabled").MustBool(threatIndicatorsEnabled)
+	minimapScale = cfg.Section("").Key("MinimapScale").MustFloat64(minimapScale)
+	subtitlesEnabled = cfg.Section("").Key("SubtitlesEnabled").MustBool(subtitlesEnabled)
+	language = cfg.Section("").Key("Language").MustString(language)
+	resolution = cfg.Section("").Key("Resolution").MustString(resolution)
+	directorEnabled = cfg.Section("").Key("DirectorEnabled").MustBool(directorEnabled)
+	difficulty = cfg.Section("").Key("Difficulty").In(difficulty, []string{"story", "normal", "hard", "nightmare"})
+	pixelPerfect = cfg.Section("").Key("PixelPerfect").MustBool(pixelPerfect)
 	playerSpeed, err = cfg.Section("Player").Key("PlayerSpeed").Float64()
 	if err != nil {
 		log.Println("Error parsing INI file:", err)
@@ -46,6 +56,11 @@ func ApplyConfigs() {
 	if err != nil {
 		log.Println("Error parsing INI file:", err)
 	}
+	playerSpeedFactorAiming = cfg.Section("Player").Key("PlayerSpeedFactorAiming").MustFloat64(playerSpeedFactorAiming)
+	playerRange = cfg.Section("Player").Key("PlayerRange").MustFloat64(playerRange)
+	playerRangeAiming = cfg.Section("Player").Key("PlayerRangeAiming").MustFloat64(playerRangeAiming)
+	playerSpreadHip = cfg.Section("Player").Key("PlayerSpreadHip").MustFloat64(playerSpreadHip)
+	playerSpreadAiming = cfg.Section("Player").Key("PlayerSpreadAiming").MustFloat64(playerSpreadAiming)
 	zombieSpeed, err = cfg.Section("Zombie").Key("ZombieSpeed").Float64()
 	if err != nil {
 		log.Println("Error parsing INI file:", err)
@@ -62,6 +77,8 @@ func ApplyConfigs() {
 	if err != nil {
 		log.Println("Error parsing INI file:", err)
 	}
+	corpseLimit = cfg.Section("Zombie").Key("CorpseLimit").MustInt(corpseLimit)
+	corpseLifetime = cfg.Section("Zombie").Key("CorpseLifetime").MustInt(corpseLifetime)
 	dogWalkingSpeed, err = cfg.Section("Dog").Key("DogWalkingSpeed").Float64()
 	if err != nil {
 		log.Println("Error parsing INI file:", err)
@@ -98,4 +115,10 @@ func ApplyConfigs() {
 	if err != nil {
 		log.Println("Error parsing INI file:", err)
 	}
+	assistInfiniteAmmo = cfg.Section("Assists").Key("InfiniteAmmo").MustBool(assistInfiniteAmmo)
+	assistSlowSprinters = cfg.Section("Assists").Key("SlowSprinters").MustBool(assistSlowSprinters)
+	assistPatientDog = cfg.Section("Assists").Key("PatientDog").MustBool(assistPatientDog)
+	colorBlindMode = cfg.Section("Accessibility").Key("ColorBlindMode").In(colorBlindMode, []string{"off", "protanopia", "deuteranopia", "tritanopia"})
+	reducedMotion = cfg.Section("Accessibility").Key("ReducedMotion").MustBool(reducedMotion)
+	toggleHoldActions = cfg.Section("Accessibility").Key("ToggleHoldActions").MustBool(toggleHoldActions)
 }
@@ -0,0 +1,83 @@
+// Use of this source code is subject to an MIT-style
+// licence which can be found in the LICENSE file.
+
+package main
+
+import (
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// corpseLimit is the most corpses that can lie around at the same time, the
+// oldest ones are removed first when there are too many
+var corpseLimit int = 64
+
+// corpseLifetime is how long (ticks) a corpse lies around before it is gone
+var corpseLifetime int = 60 * 60
+
+// How long (ticks) it takes for a corpse to fade out at the end of its life
+const corpseFadeTime = 2 * 60
+
+// Corpse is what remains of a zombie after it died
+type Corpse struct {
+	Image    *ebiten.Image // The last frame of the zombie's death animation
+	Position Coord         // Where the zombie died
+	Angle    float64       // The angle the zombie was facing when it died
+	Age      int           // How long (ticks) the corpse has been lying around
+}
+
+// Corpses is a slice of Corpse, oldest first
+type Corpses []*Corpse
+
+// Add adds a new corpse, removing the oldest one if there are too many
+func (cs *Corpses) Add(c *Corpse) {
+	if corpseLimit < 1 {
+		return
+	}
+	if len(*cs) >= corpseLimit {
+		*cs = (*cs)[len(*cs)-corpseLimit+1:]
+	}
+	*cs = append(*cs, c)
+}
+
+// Update ages all the corpses and removes the ones that have faded out
+func (cs *Corpses) Update() {
+	corpses := (*cs)[:0]
+	for _, c := range *cs {
+		c.Age++
+		if c.Age < corpseLifetime {
+			corpses = append(corpses, c)
+		}
+	}
+	*cs = corpses
+}
+
+// Draw draws all the corpses, this is intended to be drawn under the living
+func (cs Corpses) Draw(g *GameScreen) {
+	for _, c := range cs {
+		c.Draw(g)
+	}
+}
+
+// Draw draws the Corpse to the screen
+func (c *Corpse) Draw(g *GameScreen) {
+	op := &ebiten.DrawImageOptions{}
+
+	// Centre and rotate
+	op.GeoM.Translate(
+		float64(-c.Image.Bounds().Dx()/2),
+		float64(-c.Image.Bounds().Dy()/2)+zombieCenterOffset/2,
+	)
+	op.GeoM.Rotate(c.Angle + math.Pi/2)
+
+	// Fade out at the end of its life
+	if remaining := corpseLifetime - c.Age; remaining < corpseFadeTime {
+		op.ColorScale.ScaleAlpha(float32(remaining) / corpseFadeTime)
+	}
+
+	g.Camera.Surface.DrawImage(
+		c.Image,
+		g.Camera.GetTranslation(op, c.Position.X, c.Position.Y),
+	)
+}
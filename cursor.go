@@ -3,7 +3,12 @@
 
 package main
 
-import "github.com/hajimehoshi/ebiten/v2"
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
 
 // Cursor represents the mouse cursor on the screen
 type Cursor struct {
@@ -18,6 +23,7 @@ const (
 	cursorNormal int = iota
 	cursorMiss
 	cursorHit
+	cursorAiming
 )
 
 func NewCursor() *Cursor {
@@ -25,14 +31,28 @@ func NewCursor() *Cursor {
 		images: []*ebiten.Image{
 			loadImage("assets/sprites/Cursor_1.png"),
 			loadImage("assets/sprites/Cursor_2.png"),
-			loadImage("assets/sprites/Cursor_3.png"),
+			recolor(loadRawImage("assets/sprites/Cursor_3.png"), palettes["off"].Hit, palette().Hit),
+			newReticle(),
 		},
 	}
 }
 
+// newReticle draws the crosshairs shown when aiming down the sights
+func newReticle() *ebiten.Image {
+	const size, gap = 15, 3
+	const mid = float32(size) / 2
+	img := ebiten.NewImage(size, size)
+	c := color.RGBA{0xff, 0xff, 0xff, 0xe0}
+	vector.StrokeLine(img, mid, 0, mid, mid-gap, 1, c, false)
+	vector.StrokeLine(img, mid, mid+gap, mid, size, 1, c, false)
+	vector.StrokeLine(img, 0, mid, mid-gap, mid, 1, c, false)
+	vector.StrokeLine(img, mid+gap, mid, size, mid, 1, c, false)
+	vector.DrawFilledRect(img, mid-0.5, mid-0.5, 1, 1, c, false)
+	return img
+}
+
 func (c *Cursor) Update(g *GameScreen) {
-	cx, cy := ebiten.CursorPosition()
-	c.position.X, c.position.Y = float64(cx), float64(cy)
+	c.position.X, c.position.Y = cursorPosition()
 	switch g.Player.State {
 	case playerDryFire:
 		c.state = cursorMiss
@@ -44,6 +64,9 @@ func (c *Cursor) Update(g *GameScreen) {
 		}
 	default:
 		c.state = cursorNormal
+		if g.Player.Aiming {
+			c.state = cursorAiming
+		}
 	}
 	return
 }
@@ -4,7 +4,6 @@
 package main
 
 import (
-	"image/color"
 	"math"
 
 	"github.com/hajimehoshi/ebiten/v2"
@@ -49,9 +48,9 @@ func (s *DeathScreen) Update() (GameState, error) {
 
 func (s *DeathScreen) Draw(screen *ebiten.Image) {
 	if s.DogDied {
-		s.textRenderer.DrawCenered(screen, "YOUR DOG DIED")
+		s.textRenderer.DrawCenered(screen, tr("death.dog"))
 	} else {
-		s.textRenderer.DrawCenered(screen, "YOU DIED")
+		s.textRenderer.DrawCenered(screen, tr("death.player"))
 	}
 }
 
@@ -78,6 +77,8 @@ func NewDeathRenderer() *DeathRenderer {
 func (r DeathRenderer) DrawCenered(screen *ebiten.Image, text string) {
 	// targetArea := self.txtRenderer.SelectionRect(HoverText)
 	r.SetTarget(screen)
-	r.SetColor(color.RGBA{200, 68, 19, r.alpha})
+	c := palette().Death
+	c.A = r.alpha
+	r.SetColor(c)
 	r.Draw(text, screen.Bounds().Dx()/2, screen.Bounds().Dy()/2)
 }
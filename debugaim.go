@@ -7,7 +7,6 @@ package main
 
 import (
 	"image/color"
-	"math"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
@@ -17,16 +16,14 @@ func init() {
 	debuggers.Add(DebugFunc(DebugAim))
 }
 
-// DebugAim draws a line showing the direction and range of the gun
+// DebugAim draws a line showing the direction and range of the gun, stopping
+// at whatever the bullet would hit first
 func DebugAim(g *GameScreen, screen *ebiten.Image) {
-	rangeOfFire := g.Player.Range
-	sX, sY := g.Camera.GetScreenCoords(
-		g.Player.Object.Position.X-math.Cos(g.Player.Angle-math.Pi)*rangeOfFire,
-		g.Player.Object.Position.Y-math.Sin(g.Player.Angle-math.Pi)*rangeOfFire,
-	)
-	pX, pY := g.Camera.GetScreenCoords(
-		g.Player.Object.Position.X,
-		g.Player.Object.Position.Y,
-	)
+	start, end := g.Player.AimRay()
+	if hit := Raycast(g.Space, start, end, tagMobBody, tagCover); hit != nil {
+		end = hit.Point
+	}
+	sX, sY := g.Camera.GetScreenCoords(end.X, end.Y)
+	pX, pY := g.Camera.GetScreenCoords(start.X, start.Y)
 	ebitenutil.DrawLine(screen, pX, pY, sX, sY, color.Black)
 }
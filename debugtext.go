@@ -28,12 +28,14 @@ func DebugText(g *GameScreen, screen *ebiten.Image) {
 			"X: %.2f\n"+
 			"Y: %.2f\n"+
 			"Zombies: %d\n"+
+			"Stress: %.2f\n"+
 			"Progress: %.2f%%\n",
 		ebiten.ActualFPS(),
 		ebiten.ActualTPS(),
 		g.Player.Object.Position.X/32,
 		g.Player.Object.Position.Y/32,
 		len(g.Zombies),
+		g.Director.Stress,
 		float64(g.Dog.MainPath.NextPoint)/float64(len(g.Dog.MainPath.Points))*100,
 	))
 }
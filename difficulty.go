@@ -0,0 +1,97 @@
+// Use of this source code is subject to an MIT-style
+// licence which can be found in the LICENSE file.
+
+package main
+
+import (
+	"log"
+	"math"
+)
+
+// difficulty is the name of the difficulty preset picked on the start screen,
+// one of: story, normal, hard, nightmare
+var difficulty string = "normal"
+
+// Assists make the game easier on top of the difficulty preset
+var (
+	assistInfiniteAmmo  bool = false // Shooting never uses up bullets
+	assistSlowSprinters bool = false // Sprinters run slower
+	assistPatientDog    bool = false // The dog can be out of sight for longer
+)
+
+// How much the assists change things
+const (
+	assistSprinterSpeed    = 0.7
+	assistOutOfSightFactor = 2
+)
+
+// Difficulty is a preset that scales the game's settings to make it easier or
+// harder, 1 leaves a setting as it is
+type Difficulty struct {
+	Name        string  // Used to look up the name to show in the string tables
+	ZombieSpeed float64 // How fast all zombies move
+	ZombieRange float64 // How far away zombies notice you and the dog
+	OutOfSight  float64 // How long the dog can be out of sight
+	AmmoClip    float64 // How many bullets fit in the gun
+	SpawnDelay  float64 // How long spawn points wait between zombies
+}
+
+// difficulties are the difficulty presets, from easiest to hardest
+var difficulties = []Difficulty{
+	{Name: "story", ZombieSpeed: 0.75, ZombieRange: 0.75, OutOfSight: 2, AmmoClip: 1.5, SpawnDelay: 1.5},
+	{Name: "normal", ZombieSpeed: 1, ZombieRange: 1, OutOfSight: 1, AmmoClip: 1, SpawnDelay: 1},
+	{Name: "hard", ZombieSpeed: 1.15, ZombieRange: 1.25, OutOfSight: 0.75, AmmoClip: 0.85, SpawnDelay: 0.75},
+	{Name: "nightmare", ZombieSpeed: 1.3, ZombieRange: 1.5, OutOfSight: 0.5, AmmoClip: 0.7, SpawnDelay: 0.5},
+}
+
+// difficultyIndex returns where the picked difficulty is in the difficulties,
+// or normal if it isn't one of them
+func difficultyIndex() int {
+	for i, d := range difficulties {
+		if d.Name == difficulty {
+			return i
+		}
+	}
+	return 1
+}
+
+// cycleDifficulty picks the next harder or easier difficulty
+func cycleDifficulty(step int) {
+	i := (difficultyIndex() + step + len(difficulties)) % len(difficulties)
+	difficulty = difficulties[i].Name
+}
+
+// assisted is whether any of the assists are turned on
+func assisted() bool {
+	return assistInfiniteAmmo || assistSlowSprinters || assistPatientDog
+}
+
+// applyDifficulty scales the settings by the picked difficulty and assists, it
+// changes them in place so it must only be called once, when the game starts
+func (g *Game) applyDifficulty() {
+	d := difficulties[difficultyIndex()]
+	log.Printf("Playing on %s difficulty, assists: %v\n", d.Name, assisted())
+
+	zombieSpeed *= d.ZombieSpeed
+	zombieCrawlerSpeed *= d.ZombieSpeed
+	zombieSprinterSpeed *= d.ZombieSpeed
+	zombieRange *= d.ZombieRange
+	outOfSightLimit = int(float64(outOfSightLimit) * d.OutOfSight)
+	playerAmmoClipMax = max(1, int(math.Round(float64(playerAmmoClipMax)*d.AmmoClip)))
+	spawnDelay = max(1, int(float64(spawnDelay)*d.SpawnDelay))
+
+	if assistSlowSprinters {
+		zombieSprinterSpeed *= assistSprinterSpeed
+	}
+	if assistPatientDog {
+		outOfSightLimit *= assistOutOfSightFactor
+	}
+
+	g.Stat.Difficulty = d.Name
+	g.Stat.Assisted = assisted()
+
+	// The player was made before the gun's size was known
+	if gs, ok := g.Screens[gameRunning].(*GameScreen); ok && gs.Player != nil {
+		gs.Player.Ammo = playerAmmoClipMax
+	}
+}
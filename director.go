@@ -0,0 +1,148 @@
+// Use of this source code is subject to an MIT-style
+// licence which can be found in the LICENSE file.
+
+package main
+
+import (
+	"log"
+	"math"
+	"math/rand"
+)
+
+// directorEnabled lets the director change how often and which zombies spawn
+// depending on how hard a time the player is having, turn it off to always
+// spawn the same way
+var directorEnabled bool = true
+
+// How much each thing adds to the player's stress, from 0 (calm) to 1
+// (overwhelmed), starting from neutral where the pacing is the same as without
+// the director
+const (
+	directorNeutralStress = 0.5  // Where it starts and settles when nothing's happening
+	directorDeathStress   = 0.25 // For each death since the last checkpoint
+	directorAmmoStress    = 0.2  // For an empty gun, less for some bullets left
+	directorFleeStress    = 0.15 // Each time the dog runs away
+	directorFleeDecay     = 0.9995
+	directorBoredTime     = 20 * 60 // No kills for this long (ticks) is boring
+	directorBoredRelief   = directorNeutralStress
+	directorEase          = 0.01 // How quickly stress follows what's happening
+)
+
+// How the director paces the game, at no stress and at full stress, in between
+// it changes gradually from and to the usual pacing at neutral stress
+const (
+	directorCalmSpawnDelay     = 0.75 // Spawn points spawn faster when you're calm
+	directorStressedSpawnDelay = 2
+	directorSprinterChance     = 0.15 // Normal zombies become sprinters when you're calm
+	directorStressedCrawlers   = 0.5  // More normal zombies are slow crawlers when stressed
+)
+
+// Director keeps the pacing fair by watching how hard a time the player is
+// having and spawning fewer, slower zombies when they're struggling and more,
+// faster ones when they're bored
+type Director struct {
+	Stress     float64 // How stressed the player is from 0 to 1
+	Deaths     int     // Deaths since the last checkpoint
+	Flee       float64 // Stress from the dog running away, fades over time
+	SinceKill  int     // Ticks since the player last killed a zombie
+	checkpoint int
+	deaths     int
+	kills      int
+	dogState   int
+}
+
+// NewDirector makes a director that starts out with the usual pacing
+func NewDirector() *Director {
+	return &Director{Stress: directorNeutralStress}
+}
+
+// Update works out how stressed the player is from what's been happening
+func (d *Director) Update(g *GameScreen) {
+	// Count deaths since reaching the current checkpoint
+	deaths := g.Stat.CounterPlayerDied + g.Stat.CounterDogDied
+	if g.Checkpoint != d.checkpoint {
+		d.checkpoint = g.Checkpoint
+		d.deaths = deaths
+	}
+	d.Deaths = deaths - d.deaths
+
+	if g.Stat.CounterZombiesKilled != d.kills {
+		d.kills = g.Stat.CounterZombiesKilled
+		d.SinceKill = 0
+	}
+	d.SinceKill++
+
+	if g.Dog.State == dogDangerFleeing && d.dogState != dogDangerFleeing {
+		d.Flee += directorFleeStress
+	}
+	d.dogState = g.Dog.State
+	d.Flee *= directorFleeDecay
+
+	target := directorNeutralStress +
+		directorDeathStress*float64(d.Deaths) +
+		directorAmmoStress*(1-float64(g.Player.Ammo)/float64(playerAmmoClipMax)) +
+		d.Flee
+	if d.SinceKill > directorBoredTime {
+		target -= directorBoredRelief
+	}
+	target = math.Min(1, math.Max(0, target))
+	d.Stress += (target - d.Stress) * directorEase
+}
+
+// Reset forgets what happened before the player respawned, except for how
+// many times they died
+func (d *Director) Reset() {
+	d.Flee = 0
+	d.SinceKill = 0
+	log.Printf("Director: %d deaths since checkpoint, stress %.2f\n", d.Deaths, d.Stress)
+}
+
+// SpawnDelay is how long a spawn point waits before spawning another zombie
+func (d *Director) SpawnDelay() int {
+	delay := spawnDelay
+	if directorEnabled {
+		factor := 1 + (directorCalmSpawnDelay-1)*d.calm() + (directorStressedSpawnDelay-1)*d.stressed()
+		delay = max(1, int(float64(spawnDelay)*factor))
+	}
+	return delay + rand.Intn(delay)
+}
+
+// ZombieType picks which type of zombie a spawn point spawns next, spawn
+// points for normal zombies also spawn crawlers and sometimes sprinters, and
+// sprinter spawn points spawn normal zombies when the player is stressed
+func (d *Director) ZombieType(t ZombieType) ZombieType {
+	// Without the director it's always the same mix as the original game
+	sprinters, crawlers, calmed := 0.0, 1/float64(zombieVariants+1), 0.0
+	if directorEnabled {
+		sprinters = directorSprinterChance * d.calm()
+		crawlers += (directorStressedCrawlers - crawlers) * d.stressed()
+		calmed = d.stressed() / 2
+	}
+
+	r := rand.Float64()
+	switch t {
+	case zombieNormal, zombieCrawler:
+		if r < sprinters {
+			return zombieSprinter
+		}
+		if r < sprinters+crawlers {
+			return zombieCrawler
+		}
+		return zombieNormal
+	case zombieSprinter:
+		if r < calmed {
+			return zombieNormal
+		}
+	}
+	return t
+}
+
+// calm is how far below neutral the player's stress is, from 0 to 1
+func (d *Director) calm() float64 {
+	return math.Max(0, (directorNeutralStress-d.Stress)/directorNeutralStress)
+}
+
+// stressed is how far above neutral the player's stress is, from 0 to 1
+func (d *Director) stressed() float64 {
+	return math.Max(0, (d.Stress-directorNeutralStress)/(1-directorNeutralStress))
+}
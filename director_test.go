@@ -0,0 +1,103 @@
+// Use of this source code is subject to an MIT-style
+// licence which can be found in the LICENSE file.
+
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+// directorSamples is how many times each random pick is made to check how
+// often each outcome comes up
+const directorSamples = 20000
+
+func TestDirectorZombieType(t *testing.T) {
+	crawlers := 1 / float64(zombieVariants+1)
+	for _, data := range []struct {
+		Enabled bool
+		Stress  float64
+		Type    ZombieType
+		Want    map[ZombieType]float64
+		Reason  string
+	}{
+		{
+			false, 0, zombieNormal,
+			map[ZombieType]float64{zombieNormal: 1 - crawlers, zombieCrawler: crawlers},
+			"without the director it's the original mix",
+		},
+		{
+			true, directorNeutralStress, zombieNormal,
+			map[ZombieType]float64{zombieNormal: 1 - crawlers, zombieCrawler: crawlers},
+			"neutral stress is the original mix",
+		},
+		{
+			true, 0, zombieNormal,
+			map[ZombieType]float64{
+				zombieSprinter: directorSprinterChance,
+				zombieCrawler:  crawlers,
+				zombieNormal:   1 - directorSprinterChance - crawlers,
+			},
+			"calm players get sprinters",
+		},
+		{
+			true, 1, zombieNormal,
+			map[ZombieType]float64{zombieNormal: 1 - directorStressedCrawlers, zombieCrawler: directorStressedCrawlers},
+			"stressed players get more crawlers",
+		},
+		{
+			true, directorNeutralStress, zombieSprinter,
+			map[ZombieType]float64{zombieSprinter: 1},
+			"sprinter spawn points spawn sprinters at neutral stress",
+		},
+		{
+			true, 1, zombieSprinter,
+			map[ZombieType]float64{zombieSprinter: 0.5, zombieNormal: 0.5},
+			"stressed players get normal zombies instead of some sprinters",
+		},
+		{
+			true, 1, zombieBig,
+			map[ZombieType]float64{zombieBig: 1},
+			"the boss is always the boss",
+		},
+	} {
+		directorEnabled = data.Enabled
+		d := &Director{Stress: data.Stress}
+		got := map[ZombieType]float64{}
+		for i := 0; i < directorSamples; i++ {
+			got[d.ZombieType(data.Type)] += 1.0 / directorSamples
+		}
+		for _, zt := range []ZombieType{zombieNormal, zombieCrawler, zombieSprinter, zombieBig} {
+			if math.Abs(got[zt]-data.Want[zt]) > 0.02 {
+				t.Errorf("%s: got type %d %.3f of the time, want %.3f", data.Reason, zt, got[zt], data.Want[zt])
+			}
+		}
+	}
+	directorEnabled = true
+}
+
+func TestDirectorSpawnDelay(t *testing.T) {
+	for _, data := range []struct {
+		Enabled bool
+		Stress  float64
+		Factor  float64 // Shortest delay compared to the usual one
+	}{
+		{false, 1, 1},
+		{true, 0, directorCalmSpawnDelay},
+		{true, directorNeutralStress / 2, (1 + directorCalmSpawnDelay) / 2},
+		{true, directorNeutralStress, 1},
+		{true, (1 + directorNeutralStress) / 2, (1.0 + directorStressedSpawnDelay) / 2},
+		{true, 1, directorStressedSpawnDelay},
+	} {
+		directorEnabled = data.Enabled
+		d := &Director{Stress: data.Stress}
+		shortest := int(float64(spawnDelay) * data.Factor)
+		for i := 0; i < directorSamples; i++ {
+			if delay := d.SpawnDelay(); delay < shortest || delay >= shortest*2 {
+				t.Fatalf("director enabled %v at stress %.2f: got delay %d, want %d to %d",
+					data.Enabled, data.Stress, delay, shortest, shortest*2-1)
+			}
+		}
+	}
+	directorEnabled = true
+}
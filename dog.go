@@ -90,6 +90,7 @@ type Dog struct {
 	LastPathpointReached bool
 	AtCheckpointCounter  int
 	OutOfSightCounter    int
+	Terrain              *Terrain
 }
 
 func (d *Dog) Init() {
@@ -304,6 +305,11 @@ func (d *Dog) Update(g *GameScreen) {
 		d.followPath(g)
 	}
 
+	// Walking over some terrain kicks up sand, leaves etc.
+	if d.State == dogNormalWalking || d.State == dogDangerFleeing {
+		d.Terrain.KickUp(g, *d.Position(), d.Angle)
+	}
+
 	// If dog is walking then after some time a flavour voice line is played
 	if d.State == dogNormalWalking || d.State == dogNormalBlocked {
 		if g.Checkpoint > 0 && g.Checkpoint < 7 {
@@ -319,6 +325,16 @@ func (d *Dog) Update(g *GameScreen) {
 		}
 	}
 
+	// Sniffing sound loops for as long as the dog is sniffing
+	if d.State == dogNormalSniffing {
+		if !g.Sniffing.IsPlaying() {
+			g.Sniffing.Rewind()
+			g.Sniffing.Play()
+		}
+	} else if g.Sniffing.IsPlaying() {
+		g.Sniffing.Pause()
+	}
+
 	// Animate dog
 	animationFrame := d.Sprite.Meta.FrameTags[dogStateToFrame[d.State]]
 	d.Frame = Animate(d.Frame, g.Tick, animationFrame)
@@ -412,13 +428,9 @@ func (d *Dog) followPath(g *GameScreen) {
 
 // Move the Dog by the given vector if it is possible to do so
 func (d *Dog) move(dx, dy float64) {
-	// Collision detection and response between sand trap and dog
-	d.TempSpeed = 1
-	if collision := d.Object.Check(0, 0, tagSandTrap); collision != nil {
-		if d.Object.Shape.Intersection(0, 0, collision.Objects[0].Shape) != nil {
-			d.TempSpeed = sandTrapSpeedMultiplier
-		}
-	}
+	// Terrain like sand traps slows the dog down
+	d.Terrain = TerrainUnder(d.Object, entityDog)
+	d.TempSpeed = d.Terrain.SpeedFor(entityDog)
 
 	switch d.State {
 	case dogNormalWalking:
@@ -489,6 +501,11 @@ func (d *Dog) Draw(g *GameScreen) {
 	)
 }
 
+// Depth returns where the Dog's feet are for sorting the draw order
+func (d *Dog) Depth() float64 {
+	return d.Object.Position.Y + d.Object.Size.Y/2
+}
+
 // Position returns the Dog's current coordinates
 func (d *Dog) Position() *Coord {
 	return &Coord{
@@ -0,0 +1,35 @@
+// Use of this source code is subject to an MIT-style
+// licence which can be found in the LICENSE file.
+
+package main
+
+import (
+	"sort"
+)
+
+// Drawable is anything that stands on the ground and should be drawn in front
+// of things further up the screen and behind things further down
+type Drawable interface {
+	Draw(*GameScreen)
+	Depth() float64 // Where it touches the ground, the higher the more in front
+}
+
+// DrawQueue collects things to draw so they can be drawn sorted by depth
+type DrawQueue []Drawable
+
+// Add adds things to the draw queue
+func (dq *DrawQueue) Add(d ...Drawable) {
+	*dq = append(*dq, d...)
+}
+
+// Draw draws everything in the queue from back to front and empties it
+func (dq *DrawQueue) Draw(g *GameScreen) {
+	// Stable so things at the same depth don't flicker in front of each other
+	sort.SliceStable(*dq, func(i, j int) bool {
+		return (*dq)[i].Depth() < (*dq)[j].Depth()
+	})
+	for _, d := range *dq {
+		d.Draw(g)
+	}
+	*dq = (*dq)[:0]
+}
@@ -4,6 +4,7 @@
 package main
 
 import (
+	"image"
 	"image/color"
 	"log"
 	"math"
@@ -28,18 +29,17 @@ const cameraPadding = 1
 // For testing it is sometimes useful to start the game at a later checkpoint
 var startingCheckpoint int = 0
 
-// Multiplier applied when object is in sand trap
-const sandTrapSpeedMultiplier = 0.5
-
 const (
 	tagPlayer     = "player"
 	tagMob        = "mob"
+	tagMobBody    = "body" // a mob's whole body, for bullets to hit
 	tagWall       = "wall"
 	tagDog        = "dog"
 	tagEnd        = "end"
 	tagOutro      = "outro"
 	tagCheckpoint = "check"
-	tagSandTrap   = "sandtrap"
+	tagCover      = "cover"   // stops bullets
+	tagTerrain    = "terrain" // has a *Terrain as Data
 )
 
 // Length of the fading animation
@@ -57,37 +57,55 @@ const (
 
 // GameScreen is the screen for the actual main game itself
 type GameScreen struct {
-	Width          int
-	Height         int
-	Tick           int
-	TileRenderer   *TileRenderer
-	LDTKProject    *ldtkgo.Project
-	Music          *MusicLoop
-	Sounds         Sounds
-	Voices         Sounds
-	Level          int
-	Background     *ebiten.Image
-	Foreground     *ebiten.Image
-	Camera         *camera.Camera
-	Cursor         *Cursor
-	Sprites        map[SpriteType]*SpriteSheet
-	ZombieSprites  []*SpriteSheet
-	Player         *Player
-	Dog            *Dog
-	SpawnPoints    SpawnPoints
-	Zombies        Zombies
-	BossDefeated   bool
-	Space          *resolv.Space
-	LevelMap       LevelMap
-	Checkpoint     int
-	HUD            *HUD
-	Debuggers      Debuggers
-	Zoom           *Zoom
-	FadeTween      *gween.Tween
-	Alpha          uint8
-	Stat           *Stat
-	VoiceGuardTime int
-	NextVoiceStep  uint8
+	Width               int
+	Height              int
+	Tick                int
+	TileRenderer        *TileRenderer
+	LDTKProject         *ldtkgo.Project
+	Music               *MusicLoop
+	Sniffing            *MusicLoop
+	Sounds              Sounds
+	Footsteps           map[string]*Sound // Footstep sounds for terrain that has its own
+	Voices              Sounds
+	Level               int
+	Background          []*ChunkedImage
+	Foreground          []*ChunkedImage
+	DrawQueue           DrawQueue
+	FadeShader          *ebiten.Shader
+	Lighting            *Lighting
+	Director            *Director
+	Vision              *Vision
+	Indicators          *Indicators
+	Minimap             *Minimap
+	Subtitles           *SubtitleRenderer
+	Decals              []*MapDecal
+	Camera              *camera.Camera
+	CameraEffects       *CameraEffects
+	CameraControl       *CameraController
+	Cursor              *Cursor
+	Sprites             map[SpriteType]*SpriteSheet
+	ZombieSprites       []*SpriteSheet
+	Player              *Player
+	Dog                 *Dog
+	SpawnPoints         SpawnPoints
+	Zombies             Zombies
+	Corpses             Corpses
+	BossDefeated        bool
+	Space               *resolv.Space
+	LevelMap            LevelMap
+	Checkpoint          int
+	Checkpoints         int
+	CheckpointPositions map[int]Coord
+	HUD                 *HUD
+	Particles           *Particles
+	Debuggers           Debuggers
+	Zoom                *Zoom
+	FadeTween           *gween.Tween
+	Alpha               uint8
+	Stat                *Stat
+	VoiceGuardTime      int
+	NextVoiceStep       uint8
+	DeathShake          int
 }
 
 // NewGameScreen fills up the main Game data with assets, entities, pre-generated
@@ -106,11 +124,18 @@ func NewGameScreen(game *Game, loadingCount LoadingCounter) {
 	}
 
 	g.Camera = camera.NewCamera(g.Width, g.Height, 0, 0, 0, 1)
+	g.CameraEffects = NewCameraEffects()
+	g.CameraControl = NewCameraController()
 	g.Cursor = NewCursor()
+	g.Lighting = NewLighting()
+	g.Director = NewDirector()
+	g.Vision = NewVision()
 
 	*loadingCount++
 	var renderer *TileRenderer
 	ldtkProject := loadMaps("assets/maps/maps.ldtk")
+	layerDefs := loadLayerDefs("assets/maps/maps.ldtk")
+	intGridValues := layerDefs.Terrain
 	renderer = NewTileRenderer(&EmbedLoader{"assets/maps"})
 
 	g.TileRenderer = renderer
@@ -118,28 +143,71 @@ func NewGameScreen(game *Game, loadingCount LoadingCounter) {
 
 	level := g.LDTKProject.Levels[g.Level]
 
-	bg := ebiten.NewImage(level.Width, level.Height)
-	bg.Fill(level.BGColor)
-	fg := ebiten.NewImage(level.Width, level.Height)
-
-	// Render map
+	// Prepare map for rendering, only the parts on the screen are actually
+	// rendered a chunk at a time
 	g.TileRenderer.Render(level)
+	var bgLayers []*RenderedLayer
+	g.Foreground = nil
 	for _, layer := range g.TileRenderer.RenderedLayers {
-		log.Println("Pre-drawing layer:", layer.Layer.Identifier)
-		if layer.Layer.Identifier == "Treetops" {
-			// Draw black, transparent, scaled copy as fake shadows
-			op := &ebiten.DrawImageOptions{}
-			op.ColorM.Scale(0, 0, 0, 0.1)
-			op.GeoM.Translate(8, 8)
-			fg.DrawImage(layer.Image, op)
-			// Draw real trees
-			fg.DrawImage(layer.Image, &ebiten.DrawImageOptions{})
-		} else {
-			bg.DrawImage(layer.Image, &ebiten.DrawImageOptions{})
+		log.Println("Preparing layer:", layer.Layer.Identifier)
+		fl, ok := foregroundLayer(layer.Layer.Identifier)
+		if !ok {
+			bgLayers = append(bgLayers, layer)
+			continue
+		}
+		if fl.Shadow {
+			// Black, transparent copy on the ground as fake shadows
+			shadow := NewChunkedImage(level.Width, level.Height, func(chunk *ebiten.Image, bounds image.Rectangle) {
+				var cs ebiten.ColorScale
+				cs.Scale(0, 0, 0, 0.1)
+				layer.DrawChunk(chunk, bounds, image.Pt(8, 8), cs)
+			})
+			g.Foreground = append(g.Foreground, shadow)
+		}
+		fg := NewChunkedImage(level.Width, level.Height, func(chunk *ebiten.Image, bounds image.Rectangle) {
+			layer.DrawChunk(chunk, bounds, image.Point{}, ebiten.ColorScale{})
+		})
+		fg.Animated = layer
+		fg.Parallax = layerDefs.Parallax[layer.Layer.Identifier]
+		if fl.Fade {
+			if g.FadeShader == nil {
+				g.FadeShader = loadShader("assets/shaders/canopy.kage")
+			}
+			fg.Fade = g.FadeShader
+		}
+		g.Foreground = append(g.Foreground, fg)
+	}
+	// Background layers are baked together, except that a layer with animated
+	// tiles ends a batch so that its tiles are drawn below the layers above it
+	g.Background = nil
+	for len(bgLayers) > 0 {
+		n := 1
+		for n < len(bgLayers) && len(bgLayers[n-1].Animated) == 0 {
+			n++
 		}
+		layers := bgLayers[:n]
+		bgLayers = bgLayers[n:]
+		first, last := len(g.Background) == 0, len(bgLayers) == 0
+		bg := NewChunkedImage(level.Width, level.Height, func(chunk *ebiten.Image, bounds image.Rectangle) {
+			if first {
+				chunk.Fill(level.BGColor)
+			}
+			for _, layer := range layers {
+				layer.DrawChunk(chunk, bounds, image.Point{}, ebiten.ColorScale{})
+			}
+			if last {
+				for _, d := range g.Decals {
+					drawInChunk(chunk, d.Image, bounds, d.Position.X, d.Position.Y, &ebiten.DrawImageOptions{})
+				}
+			}
+		})
+		if animated := layers[n-1]; len(animated.Animated) > 0 {
+			bg.Animated = animated
+		}
+		g.Background = append(g.Background, bg)
 	}
-	g.Background = bg
-	g.Foreground = fg
+
+	g.Minimap = NewMinimap(level, intGridValues)
 
 	// Create space for collision detection
 	g.Space = resolv.NewSpace(level.Width, level.Height, 16, 16)
@@ -147,40 +215,45 @@ func NewGameScreen(game *Game, loadingCount LoadingCounter) {
 	// Create level map for A* path planning
 	g.LevelMap = CreateMap(level.Width, level.Height)
 
-	// Add wall tiles and sand traps to space for collision detection
+	// Add terrain from all IntGrid layers to space for collision detection, what
+	// kind of terrain each value is comes from its identifier in LDtk
 	for _, layer := range level.Layers {
-		if layer.Type == ldtkgo.LayerTypeIntGrid && layer.Identifier == "Desert" {
-			for _, intData := range layer.IntGrid {
-				object := resolv.NewObject(
-					float64(intData.Position[0]+layer.OffsetX),
-					float64(intData.Position[1]+layer.OffsetY),
-					float64(layer.GridSize),
-					float64(layer.GridSize),
-					tagWall,
-				)
-				object.SetShape(resolv.NewRectangle(
-					float64(intData.Position[0]+layer.OffsetX),
-					float64(intData.Position[1]+layer.OffsetY),
-					float64(layer.GridSize),
-					float64(layer.GridSize),
-				))
-				g.Space.Add(object)
+		if layer.Type != ldtkgo.LayerTypeIntGrid {
+			continue
+		}
+		unknownTerrain := map[int]bool{}
+		terrainCells := map[*Terrain][]image.Point{}
+		for _, intData := range layer.IntGrid {
+			terrain, ok := intGridValues.TerrainAt(layer.Identifier, intData.Value)
+			if !ok {
+				if !unknownTerrain[intData.Value] {
+					log.Printf("Unknown terrain %d on layer %s", intData.Value, layer.Identifier)
+					unknownTerrain[intData.Value] = true
+				}
+				continue
+			}
+			cell := image.Pt(intData.Position[0]/layer.GridSize, intData.Position[1]/layer.GridSize)
+			terrainCells[terrain] = append(terrainCells[terrain], cell)
 
-				g.LevelMap.SetObstacle(intData.Position[0]/layer.GridSize, intData.Position[1]/layer.GridSize)
+			// Path planning still needs to know about every single tile
+			if terrain.Obstacle {
+				g.LevelMap.SetObstacle(cell.X, cell.Y)
 			}
-		} else if layer.Type == ldtkgo.LayerTypeIntGrid && layer.Identifier == "Sand_Traps" {
-			for _, intData := range layer.IntGrid {
-				object := resolv.NewObject(
-					float64(intData.Position[0]+layer.OffsetX),
-					float64(intData.Position[1]+layer.OffsetY),
-					16, 16,
-					tagSandTrap,
-				)
-				object.SetShape(resolv.NewRectangle(
-					float64(intData.Position[0]+layer.OffsetX),
-					float64(intData.Position[1]+layer.OffsetY),
-					16, 16,
-				))
+		}
+
+		// Merge neighbouring tiles into big rectangles to keep collision
+		// detection fast, there would be thousands of objects otherwise
+		for terrain, cells := range terrainCells {
+			rects := MergeCells(cells)
+			log.Printf("Merged %d %s tiles into %d objects", len(cells), layer.Identifier, len(rects))
+			for _, r := range rects {
+				x := float64(r.Min.X*layer.GridSize + layer.OffsetX)
+				y := float64(r.Min.Y*layer.GridSize + layer.OffsetY)
+				w := float64(r.Dx() * layer.GridSize)
+				h := float64(r.Dy() * layer.GridSize)
+				object := resolv.NewObject(x, y, w, h, terrain.Tags...)
+				object.Data = terrain
+				object.SetShape(resolv.NewRectangle(x, y, w, h))
 				g.Space.Add(object)
 			}
 		}
@@ -190,10 +263,12 @@ func NewGameScreen(game *Game, loadingCount LoadingCounter) {
 	*loadingCount++
 	g.Music = NewMusicPlayer(loadSoundFile("assets/music/BackgroundMusic.ogg", sampleRate))
 	g.Music.SetVolume(0.5)
+	g.Sniffing = NewMusicPlayer(loadSoundFile("assets/sfx/Dog-sniffing.ogg", sampleRate))
+	g.Sniffing.SetVolume(0.7)
 
 	// Sound
 	*loadingCount++
-	howManySounds := 13
+	howManySounds := 14
 	g.Sounds = make([]*Sound, howManySounds)
 	for i := 0; i < howManySounds; i++ {
 		g.Sounds[i] = &Sound{Volume: 0.7}
@@ -211,6 +286,9 @@ func NewGameScreen(game *Game, loadingCount LoadingCounter) {
 	g.Sounds[soundBigZombieDeath1].AddSound("assets/sfx/Big-zombie-death-Phase-1", sampleRate, context)
 	g.Sounds[soundBigZombieScream].AddSound("assets/sfx/Big-zombie-scream-Phase-2", sampleRate, context)
 	g.Sounds[soundBigZombieDeath2].AddSound("assets/sfx/Big-zombie-death-Phase-2", sampleRate, context)
+	g.Sounds[soundFootstep].AddSound("assets/sfx/Footstep-loop", sampleRate, context)
+	g.Sounds[soundFootstep].Start = footstepStart
+	g.Footsteps = loadFootsteps(intGridValues)
 
 	// Voices
 	howManyVoices := 5
@@ -260,7 +338,9 @@ func NewGameScreen(game *Game, loadingCount LoadingCounter) {
 	playerPosition := entities.EntityByIdentifier("Player").Position
 	g.Player = NewPlayer(playerPosition, g.Sprites[spritePlayer])
 	g.Space.Add(g.Player.Object)
+	g.CameraControl.Snap(*g.Player.Position())
 
+	g.CheckpointPositions = map[int]Coord{}
 	for _, e := range entities.Entities {
 		if strings.HasPrefix(e.Identifier, "Checkpoint") {
 			eid, err := strconv.Atoi(e.Identifier[11:])
@@ -271,15 +351,23 @@ func NewGameScreen(game *Game, loadingCount LoadingCounter) {
 			log.Println(e.Identifier, e.Position)
 			img := loadEntityImage(e.Identifier)
 			w, h := img.Size()
-			op := &ebiten.DrawImageOptions{}
-			op.GeoM.Translate(float64(e.Position[0]), float64(e.Position[1]))
-			g.Background.DrawImage(img, op)
+			g.Decals = append(g.Decals, &MapDecal{
+				Image:    img,
+				Position: Coord{float64(e.Position[0]), float64(e.Position[1])},
+			})
 			obj := resolv.NewObject(
 				float64(e.Position[0]), float64(e.Position[1]),
 				float64(w), float64(h),
 				tagCheckpoint,
 			)
 			obj.Data = eid
+			g.CheckpointPositions[eid] = Coord{
+				X: float64(e.Position[0]) + float64(w)/2,
+				Y: float64(e.Position[1]) + float64(h)/2,
+			}
+			if eid > g.Checkpoints {
+				g.Checkpoints = eid
+			}
 			g.Space.Add(obj)
 		}
 	}
@@ -338,6 +426,9 @@ func NewGameScreen(game *Game, loadingCount LoadingCounter) {
 	}
 
 	g.HUD = NewHUD()
+	g.Indicators = NewIndicators()
+	g.Subtitles = NewSubtitleRenderer()
+	g.Particles = NewParticles()
 	g.Zoom = NewZoom()
 
 	*loadingCount++
@@ -352,6 +443,13 @@ func (g *GameScreen) Start() {
 	g.Stat.GameStarted = time.Now()
 }
 
+// Stop stops sounds that would otherwise keep playing after leaving the game,
+// e.g. when you die
+func (g *GameScreen) Stop() {
+	g.Sniffing.Pause()
+	stopFootsteps(g, nil)
+}
+
 // Reset is similar to NewGameScreen but only resets the things that should be
 // changed when you reset/restart the game, without reloading all the media
 func (g *GameScreen) Reset(game *Game) {
@@ -368,6 +466,14 @@ func (g *GameScreen) Reset(game *Game) {
 	}
 	g.Zombies = Zombies{}
 
+	// Remove corpses, blood and other effects
+	g.Corpses = Corpses{}
+	g.Particles.Clear()
+	g.HUD.Banner = ""
+	g.HUD.BannerFader = nil
+
+	g.Director.Reset()
+
 	// Reset spawnpoints
 	for _, s := range g.SpawnPoints {
 		s.Reset()
@@ -383,11 +489,14 @@ func (g *GameScreen) Reset(game *Game) {
 	}
 	g.Player.Object.Position.X, g.Player.Object.Position.Y = float64(startPos[0]), float64(startPos[1])
 	g.Dog.Reset(g.Checkpoint, float64(startPos[0]+dogOffset), float64(startPos[1]))
+	g.CameraControl.Snap(*g.Player.Position())
 
 	g.Music.FadeIn()
 	g.Voices[voiceRespawn].Play()
 	g.VoiceGuardTime = 0
 	g.Zoom = NewZoom()
+	g.CameraEffects = NewCameraEffects()
+	g.DeathShake = 0
 	game.State = gameRunning
 }
 
@@ -395,6 +504,17 @@ func (g *GameScreen) Update() (GameState, error) {
 	g.Tick++
 	g.VoiceGuardTime++
 
+	// Freeze the screen for a moment while it shakes after the player died
+	if g.DeathShake > 0 {
+		g.DeathShake--
+		g.CameraEffects.Update()
+		g.positionCamera()
+		if g.DeathShake == 0 {
+			return gameOver, nil
+		}
+		return gameRunning, nil
+	}
+
 	// Pressing X any time quits immediately
 	if ebiten.IsKeyPressed(ebiten.KeyX) {
 		// literally copied this whole code from the player-zombie collision section below
@@ -411,6 +531,13 @@ func (g *GameScreen) Update() (GameState, error) {
 		g.Alpha = uint8(alpha)
 	}
 
+	// Freeze the action for a few ticks to give hits extra impact
+	g.CameraEffects.Update()
+	if g.CameraEffects.Stopped() {
+		g.positionCamera()
+		return gameRunning, nil
+	}
+
 	// Pressing R reloads the ammo
 	if inpututil.IsKeyJustPressed(ebiten.KeyR) {
 		switch g.Player.State {
@@ -420,14 +547,34 @@ func (g *GameScreen) Update() (GameState, error) {
 		}
 	}
 
+	// Pressing C switches between following the player and framing the dog too
+	if inpututil.IsKeyJustPressed(ebiten.KeyC) {
+		g.CameraControl.ToggleMode()
+	}
+
+	if inpututil.IsKeyJustPressed(ebiten.KeyM) {
+		g.Minimap.Toggle()
+	}
+
 	// Gun shooting handler
 	if clicked() {
 		Shoot(g)
 	}
 
+	// Holding right click aims down the sights and zooms in, or each click
+	// switches between aiming and not when hold actions are toggles
+	if toggleHoldActions {
+		if aimPressed() {
+			g.Player.Aiming = !g.Player.Aiming
+		}
+	} else {
+		g.Player.Aiming = aiming()
+	}
+	g.Zoom.On = g.Player.Aiming
+
 	// Zoom handling
 	g.Zoom.Update()
-	g.Camera.SetZoom(g.Zoom.Amount)
+	g.Camera.SetZoom(g.Zoom.Amount * g.CameraControl.Zoom)
 
 	// Update player
 	g.Player.Update(g)
@@ -439,8 +586,17 @@ func (g *GameScreen) Update() (GameState, error) {
 	g.Zombies.Update(g)
 
 	// Update spawn points
+	g.Director.Update(g)
 	g.SpawnPoints.Update(g)
 
+	// Update corpses
+	g.Corpses.Update()
+
+	// Update particle effects
+	g.Particles.Update()
+	g.Lighting.Update(g)
+	g.HUD.Update()
+
 	// Update cursor
 	g.Cursor.Update(g)
 
@@ -454,7 +610,9 @@ func (g *GameScreen) Update() (GameState, error) {
 				g.Music.Pause()
 				g.Sounds[soundPlayerDies].Play()
 				g.Stat.CounterPlayerDied++
-				return gameOver, nil // return early, no point in continuing, you are dead
+				g.CameraEffects.AddTrauma(traumaPlayerDies)
+				g.DeathShake = deathShakeTime
+				return gameRunning, nil // return early, no point in continuing, you are dead
 			}
 		}
 	}
@@ -469,6 +627,7 @@ func (g *GameScreen) Update() (GameState, error) {
 					g.VoiceGuardTime = 0
 					g.NextVoiceStep = voiceStepFlavour1
 					g.Dog.ContinueFromCheckpoint()
+					g.HUD.ShowBanner(tr("hud.checkpointReached", g.Checkpoint, g.Checkpoints))
 				}
 			}
 
@@ -506,12 +665,14 @@ func (g *GameScreen) Update() (GameState, error) {
 		return gameOver, nil
 	}
 
-	// Position camera and clamp in to the Map dimensions
-	level := g.LDTKProject.Levels[g.Level]
-	g.Camera.SetPosition(
-		math.Min(math.Max(g.Player.Object.Position.X, float64(g.Width)/2), float64(level.Width)-float64(g.Width)/2),
-		math.Min(math.Max(g.Player.Object.Position.Y, float64(g.Height)/2), float64(level.Height)-float64(g.Height)/2),
-	)
+	// Smoothly move the camera along
+	g.CameraControl.Update(g)
+	g.positionCamera()
+
+	// Work out what the player can see from the new position
+	if fogOfWarEnabled {
+		g.Vision.Update(g)
+	}
 
 	// Retroactively unstick object that collide from small rotations
 	if collision := g.Player.Object.Check(0, 0); collision != nil {
@@ -526,33 +687,60 @@ func (g *GameScreen) Update() (GameState, error) {
 	return gameRunning, nil
 }
 
+// positionCamera positions the camera where the camera controller is looking,
+// clamps it in to the Map dimensions and then applies camera effects like
+// shaking on top
+func (g *GameScreen) positionCamera() {
+	level := g.LDTKProject.Levels[g.Level]
+	pos := g.CameraControl.Position
+	ox, oy := g.CameraEffects.Offset()
+	w, h := float64(g.Width)/g.Camera.Scale/2, float64(g.Height)/g.Camera.Scale/2
+	g.Camera.SetPosition(
+		math.Min(math.Max(pos.X, w), float64(level.Width)-w)+ox,
+		math.Min(math.Max(pos.Y, h), float64(level.Height)-h)+oy,
+	)
+}
+
 func (g *GameScreen) Draw(screen *ebiten.Image) {
 	g.Camera.Surface.Clear()
 
 	// Ground, walls and other lowest-level stuff needs to be drawn first
-	g.Camera.Surface.DrawImage(
-		g.Background,
-		g.Camera.GetTranslation(&ebiten.DrawImageOptions{}, 0, 0),
-	)
+	for _, bg := range g.Background {
+		bg.Draw(g)
+	}
+
+	// Blood stains on the ground
+	g.Particles.DrawDecals(g)
 
-	// Dog
-	g.Dog.Draw(g)
+	// Dead zombies under the living
+	g.Corpses.Draw(g)
 
-	// Player
-	g.Player.Draw(g)
+	// Dog, player and zombies, whatever is further down the screen in front
+	g.DrawQueue.Add(g.Dog, g.Player)
+	g.Zombies.Queue(g)
+	g.DrawQueue.Draw(g)
 
-	// Zombies
-	g.Zombies.Draw(g)
+	// Gunfire, blood and dust effects
+	g.Particles.Draw(g)
 
 	// Tree tops etc. high-up stuff need to be drawn above the entities
-	g.Camera.Surface.DrawImage(
-		g.Foreground,
-		g.Camera.GetTranslation(&ebiten.DrawImageOptions{}, 0, 0),
-	)
+	for _, fg := range g.Foreground {
+		fg.Draw(g)
+	}
+
+	// Cover up whatever the player can't see
+	g.Vision.Draw(g)
+
+	// Time of day, flashlight and muzzle flash
+	g.Lighting.Draw(g)
 
 	g.Camera.Blit(screen)
 
 	g.HUD.Draw(g.Player.Ammo, screen)
+	g.HUD.DrawObjective(g, screen)
+	g.Indicators.Draw(g, screen)
+	g.Minimap.Draw(g, screen)
+	g.Subtitles.DrawVoices(screen, g.Voices)
 
 	if g.Player.State != playerReload {
 		g.Cursor.Draw(screen)
@@ -571,9 +759,32 @@ func clicked() bool {
 	return inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft)
 }
 
-// Clicked is shorthand for when the right mouse button has just been clicked
-func clickedRight() bool {
-	return inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonRight)
+// aiming is shorthand for when the right mouse button or the left trigger on
+// any gamepad is being held down
+func aiming() bool {
+	if ebiten.IsMouseButtonPressed(ebiten.MouseButtonRight) {
+		return true
+	}
+	for _, id := range ebiten.AppendGamepadIDs(nil) {
+		if ebiten.IsStandardGamepadButtonPressed(id, ebiten.StandardGamepadButtonFrontBottomLeft) {
+			return true
+		}
+	}
+	return false
+}
+
+// aimPressed is shorthand for when the right mouse button or the left trigger
+// on any gamepad has just been pressed
+func aimPressed() bool {
+	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonRight) {
+		return true
+	}
+	for _, id := range ebiten.AppendGamepadIDs(nil) {
+		if inpututil.IsStandardGamepadButtonJustPressed(id, ebiten.StandardGamepadButtonFrontBottomLeft) {
+			return true
+		}
+	}
+	return false
 }
 
 // Shoot sets shooting states and also die states for any zombies in range
@@ -598,32 +809,33 @@ func Shoot(g *GameScreen) {
 		}
 
 		g.Sounds[soundGunShot].Play()
+		g.CameraEffects.AddTrauma(traumaGunShot)
+		g.CameraEffects.Kick(g.Player.Angle)
 
 		g.Stat.CounterBulletsFired++
-		g.Player.Ammo--
+		if !assistInfiniteAmmo {
+			g.Player.Ammo--
+		}
 		g.Player.State = playerShooting
-		rangeOfFire := g.Player.Range
-		sX, sY := g.Space.WorldToSpace(
-			g.Player.Object.Position.X-math.Cos(g.Player.Angle-math.Pi)*rangeOfFire,
-			g.Player.Object.Position.Y-math.Sin(g.Player.Angle-math.Pi)*rangeOfFire,
-		)
-		pX, pY := g.Space.WorldToSpace(
-			g.Player.Object.Position.X+g.Player.Object.Size.X/2,
-			g.Player.Object.Position.Y+g.Player.Object.Size.Y/2,
-		)
-		cells := g.Space.CellsInLine(pX, pY, sX, sY)
-		for _, c := range cells {
-			for _, o := range c.Objects {
-				if o.HasTags(tagMob) {
-					log.Println("HIT!")
-					g.Cursor.Hit = true
-					o.Data.(*Zombie).Hit(g)
-					return // stop at the first zombie
-				} else {
-					g.Cursor.Hit = false
-				}
+		start, end := g.Player.ShotRay()
+		hit := Raycast(g.Space, start, end, tagMobBody, tagCover)
+		if hit != nil {
+			end = hit.Point
+		}
+		muzzle := g.Player.MuzzlePosition()
+		g.Particles.EmitMuzzleFlash(muzzle, g.Player.Angle)
+		g.Lighting.MuzzleFlash(muzzle)
+		g.Particles.EmitTracer(muzzle, Coord{X: end.X, Y: end.Y})
+		if hit == nil || !hit.Object.HasTags(tagMobBody) {
+			if hit != nil {
+				g.Particles.EmitDust(Coord{X: end.X, Y: end.Y}, g.Player.Angle)
 			}
+			g.Cursor.Hit = false
+			return // missed everything or the bullet stopped in a wall
 		}
+		log.Println("HIT!")
+		g.Cursor.Hit = true
+		hit.Object.Data.(*Zombie).Hit(g)
 	}
 }
 
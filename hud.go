@@ -4,7 +4,14 @@
 package main
 
 import (
+	"image/color"
+	"math"
+
 	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/tanema/gween"
+	"github.com/tanema/gween/ease"
+	"github.com/tinne26/etxt"
 )
 
 // HudImage are images for use in the HUD
@@ -17,26 +24,66 @@ const (
 
 var hudPadding int = 5
 
-// HUD is a display showing information during the game
-// So far it only shows how much ammo you have left
+// How long (ticks) banners fade in, stay on the screen and fade out
+const (
+	bannerFadeTime = 20
+	bannerShowTime = 120
+)
+
+// Size (pixels) of the progress bar to the next checkpoint
+const (
+	progressBarWidth  = 60
+	progressBarHeight = 3
+)
+
+// Colours of the objective display
+var (
+	colorHudText        = color.RGBA{0xff, 0xff, 0xff, 0xff}
+	colorProgressBar    = color.RGBA{0xf0, 0xc0, 0x40, 0xff}
+	colorProgressBarBg  = color.RGBA{0x00, 0x00, 0x00, 0x90}
+	colorBanner         = color.RGBA{0xf0, 0xe0, 0xb0, 0xff}
+	colorBannerBackdrop = color.RGBA{0x00, 0x00, 0x00, 0x80}
+)
+
+// HUD is a display showing information during the game, like how much ammo
+// you have left and how far along you are
 type HUD struct {
-	Images []*ebiten.Image
+	Images       []*ebiten.Image
+	Text         *etxt.Renderer
+	BannerText   *etxt.Renderer
+	Banner       string          // Message shown across the screen, e.g. when reaching a checkpoint
+	BannerFader  *gween.Sequence // Fades the banner in and out
+	BannerAlpha  float32
+	checkpointAt map[int]int // Index of the closest point on the dog's path to each checkpoint
 }
 
 // NewHUD initialises a new HUD with its graphics
 func NewHUD() *HUD {
+	text := etxt.NewStdRenderer()
+	text.SetFont(loadFont("assets/fonts/PixelOperator8.ttf"))
+	text.SetAlign(etxt.Top, etxt.Right)
+	text.SetSizePx(8)
+
+	banner := etxt.NewStdRenderer()
+	banner.SetFont(loadFont("assets/fonts/OptimusPrincepsSemiBold.otf"))
+	banner.SetAlign(etxt.YCenter, etxt.XCenter)
+	banner.SetSizePx(18)
+
 	return &HUD{
 		Images: []*ebiten.Image{
 			loadImage("assets/sprites/Bullet.png"),
 			loadImage("assets/sprites/Casing.png"),
 		},
+		Text:         text,
+		BannerText:   banner,
+		checkpointAt: map[int]int{},
 	}
 }
 
 // Draw draws the HUD onto the screen, this is intended to be drawn onto the
 // game screen with current camera view *after* the camera surface has been
 // blitted to the screen
-func (hud HUD) Draw(ammo int, screen *ebiten.Image) {
+func (hud *HUD) Draw(ammo int, screen *ebiten.Image) {
 	corner := screen.Bounds().Max
 	op := &ebiten.DrawImageOptions{}
 	op.GeoM.Translate(
@@ -54,3 +101,122 @@ func (hud HUD) Draw(ammo int, screen *ebiten.Image) {
 		screen.DrawImage(bullet, op)
 	}
 }
+
+// ShowBanner shows a short message across the screen for a little while
+func (hud *HUD) ShowBanner(message string) {
+	hud.Banner = message
+	hud.BannerFader = gween.NewSequence(
+		gween.New(0, 1, bannerFadeTime, ease.OutQuad),
+		gween.New(1, 1, bannerShowTime, ease.Linear),
+		gween.New(1, 0, bannerFadeTime, ease.InQuad),
+	)
+}
+
+// Update fades the banner in and out
+func (hud *HUD) Update() {
+	if hud.BannerFader == nil {
+		return
+	}
+	alpha, _, done := hud.BannerFader.Update(1)
+	hud.BannerAlpha = alpha
+	if done {
+		hud.Banner = ""
+		hud.BannerFader = nil
+	}
+}
+
+// DrawObjective draws how far along the level you are and what the dog is
+// doing in the top-right corner, and the banner if there is one
+func (hud *HUD) DrawObjective(g *GameScreen, screen *ebiten.Image) {
+	right := screen.Bounds().Dx() - hudPadding
+	y := hudPadding
+
+	hud.Text.SetTarget(screen)
+	hud.Text.SetColor(colorHudText)
+	if g.Checkpoint < g.Checkpoints {
+		hud.Text.Draw(tr("hud.checkpoint", g.Checkpoint, g.Checkpoints), right, y)
+	} else {
+		hud.Text.Draw(tr("hud.end"), right, y)
+	}
+	y += 10
+
+	// Progress bar to the next checkpoint
+	progress := float32(hud.progress(g))
+	vector.DrawFilledRect(screen, float32(right-progressBarWidth), float32(y), progressBarWidth, progressBarHeight, colorProgressBarBg, false)
+	vector.DrawFilledRect(screen, float32(right-progressBarWidth), float32(y), progressBarWidth*progress, progressBarHeight, colorProgressBar, false)
+	y += progressBarHeight + 4
+
+	status, danger := dogStatus(g.Dog)
+	if danger {
+		hud.Text.SetColor(palette().Danger)
+	}
+	hud.Text.Draw(status, right, y)
+
+	if hud.Banner != "" {
+		w, h := screen.Bounds().Dx(), screen.Bounds().Dy()
+		backdrop := colorBannerBackdrop
+		backdrop.A = uint8(float32(backdrop.A) * hud.BannerAlpha)
+		vector.DrawFilledRect(screen, 0, float32(h/3-14), float32(w), 28, backdrop, false)
+		c := colorBanner
+		c.A = uint8(float32(c.A) * hud.BannerAlpha)
+		hud.BannerText.SetTarget(screen)
+		hud.BannerText.SetColor(c)
+		hud.BannerText.Draw(hud.Banner, w/2, h/3)
+	}
+}
+
+// progress returns how far (0 to 1) the dog is along its path from the last
+// checkpoint to the next one, or to the end after the last checkpoint
+func (hud *HUD) progress(g *GameScreen) float64 {
+	path := g.Dog.MainPath
+	if path == nil || len(path.Points) < 2 {
+		return 0
+	}
+	from := hud.pathIndex(g, g.Checkpoint)
+	to := len(path.Points) - 1
+	if g.Checkpoint < g.Checkpoints {
+		to = hud.pathIndex(g, g.Checkpoint+1)
+	}
+	if to <= from {
+		return 1
+	}
+	return math.Min(math.Max(float64(path.NextPoint-from)/float64(to-from), 0), 1)
+}
+
+// pathIndex returns the index of the point on the dog's path closest to the
+// checkpoint, the start of the path for checkpoint 0
+func (hud *HUD) pathIndex(g *GameScreen, checkpoint int) int {
+	if i, ok := hud.checkpointAt[checkpoint]; ok {
+		return i
+	}
+	i := 0
+	if c, ok := g.CheckpointPositions[checkpoint]; ok {
+		i = g.Dog.findClosestPathPoint(c.X, c.Y)
+	}
+	hud.checkpointAt[checkpoint] = i
+	return i
+}
+
+// dogStatus describes what the dog is doing and whether it's in trouble
+func dogStatus(d *Dog) (string, bool) {
+	if d.OutOfSightCounter > 0 {
+		return tr("dog.outOfSight"), true
+	}
+	switch d.State {
+	case dogNormalWaiting:
+		return tr("dog.waiting"), false
+	case dogNormalWalking:
+		return tr("dog.walking"), false
+	case dogNormalBlocked:
+		return tr("dog.blocked"), false
+	case dogNormalSniffing:
+		return tr("dog.sniffing"), false
+	case dogNormalWaitingAtCheckpoint:
+		return tr("dog.atCheckpoint"), false
+	case dogDangerBarking:
+		return tr("dog.barking"), true
+	case dogDangerFleeing:
+		return tr("dog.fleeing"), true
+	}
+	return "", false
+}
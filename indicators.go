@@ -0,0 +1,117 @@
+// Use of this source code is subject to an MIT-style
+// licence which can be found in the LICENSE file.
+
+package main
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// threatIndicatorsEnabled is whether arrows also point at sprinters and the
+// boss coming for the player or the dog from off the screen
+var threatIndicatorsEnabled bool = true
+
+// How far (pixels) from the edge of the screen the arrows are drawn
+const indicatorPadding = 12
+
+// How much the dog arrow grows when pulsing, and how fast it pulses (radians
+// per tick) when the dog has only just gone out of sight and when it's about
+// to be lost for good
+const (
+	indicatorPulseSize     = 0.35
+	indicatorPulseSlowest  = 0.05
+	indicatorPulseFastest  = 0.4
+	indicatorThreatScale   = 0.75
+	indicatorThreatOpacity = 0.8
+)
+
+// Colours of the arrows pointing at the dog and at threats, the dog arrow
+// turns to the palette's danger colour when it's about to be lost
+var (
+	colorIndicatorDog    = color.RGBA{0xff, 0xff, 0xff, 0xff}
+	colorIndicatorThreat = color.RGBA{0xff, 0x90, 0x20, 0xff}
+)
+
+// Indicators are arrows on the edge of the screen pointing at things that are
+// off the screen, like the dog when it has wandered off
+type Indicators struct {
+	Arrow *ebiten.Image
+}
+
+// NewIndicators creates the off-screen indicators with their graphics
+func NewIndicators() *Indicators {
+	return &Indicators{
+		Arrow: loadImage("assets/sprites/Arrow.png"),
+	}
+}
+
+// Draw draws arrows at the edge of the screen pointing at the dog and threats
+// that are off the screen, this is intended to be drawn onto the screen along
+// with the HUD
+func (ind *Indicators) Draw(g *GameScreen, screen *ebiten.Image) {
+	if threatIndicatorsEnabled {
+		for _, z := range g.Zombies {
+			if z.Type() != zombieSprinter && z.Type() != zombieBig {
+				continue
+			}
+			// Don't give away zombies hidden behind walls
+			pos := z.Position()
+			if !z.Chasing(g) || !g.Vision.InLineOfSight(g, *pos) {
+				continue
+			}
+			ind.draw(g, screen, *pos, indicatorThreatScale, colorIndicatorThreat, indicatorThreatOpacity)
+		}
+	}
+
+	if g.Dog.Mode == dogDead {
+		return
+	}
+	// Pulse faster and in the danger colour the closer the dog is to being lost
+	danger := math.Min(1, float64(g.Dog.OutOfSightCounter)/float64(outOfSightLimit))
+	speed := indicatorPulseSlowest + (indicatorPulseFastest-indicatorPulseSlowest)*danger
+	if reducedMotion {
+		speed = 0
+	}
+	pulse := 1 + indicatorPulseSize*danger*math.Abs(math.Sin(float64(g.Tick)*speed))
+	ind.draw(g, screen, *g.Dog.Position(), pulse, mixColor(colorIndicatorDog, palette().Danger, danger), 1)
+}
+
+// draw draws an arrow at the edge of the screen pointing at the position if
+// it's off the screen
+func (ind *Indicators) draw(g *GameScreen, screen *ebiten.Image, position Coord, scale float64, c color.RGBA, opacity float32) {
+	sx, sy := g.Camera.GetScreenCoords(position.X, position.Y)
+	w, h := float64(g.Width), float64(g.Height)
+	if sx >= 0 && sy >= 0 && sx <= w && sy <= h {
+		return
+	}
+
+	// Move along the line from the middle of the screen until the edge
+	dx, dy := sx-w/2, sy-h/2
+	t := math.Min(
+		(w/2-indicatorPadding)/math.Max(math.Abs(dx), 1),
+		(h/2-indicatorPadding)/math.Max(math.Abs(dy), 1),
+	)
+
+	op := &ebiten.DrawImageOptions{}
+	aw, ah := ind.Arrow.Bounds().Dx(), ind.Arrow.Bounds().Dy()
+	op.GeoM.Translate(-float64(aw)/2, -float64(ah)/2)
+	op.GeoM.Scale(scale, scale)
+	// The arrow image points up
+	op.GeoM.Rotate(math.Atan2(dy, dx) + math.Pi/2)
+	op.GeoM.Translate(w/2+dx*t, h/2+dy*t)
+	op.ColorScale.ScaleWithColor(c)
+	op.ColorScale.ScaleAlpha(opacity)
+	op.Filter = ebiten.FilterLinear
+	screen.DrawImage(ind.Arrow, op)
+}
+
+// mixColor mixes two colours, t is how much of the second one from 0 to 1
+func mixColor(a, b color.RGBA, t float64) color.RGBA {
+	mix := func(x, y uint8) uint8 {
+		return uint8(float64(x) + (float64(y)-float64(x))*t)
+	}
+	return color.RGBA{mix(a.R, b.R), mix(a.G, b.G), mix(a.B, b.B), mix(a.A, b.A)}
+}
@@ -23,18 +23,26 @@ type IntroScreen struct {
 	skipTextFader    *gween.Sequence
 	Tick             int
 	IntroVoice       *audio.Player
+	subtitles        *SubtitleRenderer
+	introSubtitles   SubtitleTrack
 }
 
 func NewIntroScreen(game *Game) *IntroScreen {
 	fadeSeq := gween.NewSequence(gween.New(50, 200, 60, ease.OutQuad))
 	fadeSeq.SetYoyo(true)
 
+	// Keep clear of the skip text at the bottom
+	subtitles := NewSubtitleRenderer()
+	subtitles.Margin = subtitleMargin * 3
+
 	return &IntroScreen{
 		textRenderer:     NewIntroRenderer(),
 		skipTextRenderer: NewSkipTextRenderer(),
 		textFader:        gween.New(0xff, 0, fadeOutTime, ease.OutQuad),
 		skipTextFader:    fadeSeq,
 		IntroVoice:       NewSoundPlayer(loadSoundFile("assets/voice/Intro.ogg", sampleRate)),
+		subtitles:        subtitles,
+		introSubtitles:   subtitleTrack("assets/voice/Intro.ogg"),
 	}
 }
 
@@ -52,6 +60,9 @@ func (s *IntroScreen) Update() (GameState, error) {
 	s.Tick++
 
 	alpha, _, _ := s.skipTextFader.Update(1)
+	if reducedMotion {
+		alpha = 200 // Don't flash the text
+	}
 	s.skipTextRenderer.alpha = uint8(alpha)
 
 	if s.Tick > introVoiceLength {
@@ -67,11 +78,13 @@ func (s *IntroScreen) Update() (GameState, error) {
 func (s *IntroScreen) Draw(screen *ebiten.Image) {
 	s.textRenderer.SetColor(color.RGBA{0xff, 0xff, 0xff, s.textRenderer.alpha})
 	s.textRenderer.Renderer.SetTarget(screen)
-	s.textRenderer.Renderer.Draw("In the middle of nowhere", screen.Bounds().Dx()/2, screen.Bounds().Dy()/2)
+	s.textRenderer.Renderer.Draw(tr("intro.title"), screen.Bounds().Dx()/2, screen.Bounds().Dy()/2)
 
 	s.skipTextRenderer.SetColor(color.RGBA{0xff, 0xff, 0xff, s.skipTextRenderer.alpha})
 	s.skipTextRenderer.Renderer.SetTarget(screen)
-	s.skipTextRenderer.Renderer.Draw("Press S to skip intro", screen.Bounds().Dx()/2, screen.Bounds().Dy()/8*7)
+	s.skipTextRenderer.Renderer.Draw(tr("intro.skip"), screen.Bounds().Dx()/2, screen.Bounds().Dy()/8*7)
+
+	s.subtitles.Draw(screen, s.IntroVoice, s.introSubtitles)
 }
 
 // IntroRenderer wraps etxt.Renderer to draw text
@@ -0,0 +1,92 @@
+// Use of this source code is subject to an MIT-style
+// licence which can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"path"
+	"strings"
+
+	"github.com/tinne26/etxt"
+)
+
+// language is the code of the language on-screen text is shown in, there has
+// to be a file for it in assets/lang
+var language string = "en"
+
+// defaultLanguage is used for any text missing from the chosen language
+const defaultLanguage = "en"
+
+// Language is a table of all the on-screen text in one language
+type Language struct {
+	Name     string            `json:"name"`     // Name of the language in the language itself
+	Fonts    map[string]string `json:"fonts"`    // Fonts to use instead of the usual ones, by file name
+	Fallback []string          `json:"fallback"` // Fonts to try if the usual ones are missing letters the language needs
+	Strings  map[string]string `json:"strings"`  // The text to show for each key, as a format string
+}
+
+// languages are the languages that have been loaded so far, by code
+var languages = map[string]*Language{}
+
+// loadLanguage loads the language with the code from the embedded FS, or
+// returns nil if there's no such language
+func loadLanguage(code string) *Language {
+	if l, ok := languages[code]; ok {
+		return l
+	}
+	name := path.Join("assets", "lang", code+".json")
+	data, err := assets.ReadFile(name)
+	if err != nil {
+		log.Printf("error reading file %s: %v\n", name, err)
+		languages[code] = nil
+		return nil
+	}
+	l := &Language{}
+	if err := json.Unmarshal(data, l); err != nil {
+		log.Printf("error parsing file %s as language: %v\n", name, err)
+		l = nil
+	}
+	languages[code] = l
+	return l
+}
+
+// currentLanguage returns the chosen language, or the default one if the
+// chosen one can't be loaded
+func currentLanguage() *Language {
+	if l := loadLanguage(language); l != nil {
+		return l
+	}
+	return loadLanguage(defaultLanguage)
+}
+
+// tr returns the text for the key in the chosen language, formatted with the
+// arguments, falling back to the default language and then the key itself
+func tr(key string, args ...any) string {
+	text := key
+	if l := currentLanguage(); l != nil && l.Strings[key] != "" {
+		text = l.Strings[key]
+	} else if l := loadLanguage(defaultLanguage); l != nil && l.Strings[key] != "" {
+		text = l.Strings[key]
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(text, args...)
+	}
+	return text
+}
+
+// MissingRunes returns the letters needed for the language that the font
+// doesn't have
+func (l *Language) MissingRunes(font *etxt.Font) []rune {
+	var all strings.Builder
+	for _, s := range l.Strings {
+		all.WriteString(s)
+	}
+	missing, err := etxt.GetMissingRunes(font, all.String())
+	if err != nil {
+		log.Println("error checking font for missing letters:", err)
+	}
+	return missing
+}
@@ -88,6 +88,64 @@ func (m LevelMap) SetObstacle(x, y int) {
 	m[y][x] = 1
 }
 
+// MergeCells merges grid cells into as few rectangles as possible covering the
+// same area, by greedily growing each rectangle first sideways and then
+// downwards from its top-left cell
+func MergeCells(cells []image.Point) []image.Rectangle {
+	if len(cells) == 0 {
+		return nil
+	}
+
+	bounds := image.Rectangle{Min: cells[0], Max: cells[0].Add(image.Pt(1, 1))}
+	for _, c := range cells {
+		bounds = bounds.Union(image.Rectangle{Min: c, Max: c.Add(image.Pt(1, 1))})
+	}
+
+	// Cells still waiting to be covered by a rectangle
+	todo := make([][]bool, bounds.Dy())
+	for i := range todo {
+		todo[i] = make([]bool, bounds.Dx())
+	}
+	for _, c := range cells {
+		todo[c.Y-bounds.Min.Y][c.X-bounds.Min.X] = true
+	}
+
+	var rects []image.Rectangle
+	for y := range todo {
+		for x := range todo[y] {
+			if !todo[y][x] {
+				continue
+			}
+
+			// Grow right as far as possible
+			w := 1
+			for x+w < len(todo[y]) && todo[y][x+w] {
+				w++
+			}
+
+			// Grow down while the whole row below is also waiting
+			h := 1
+		grow:
+			for y+h < len(todo) {
+				for i := x; i < x+w; i++ {
+					if !todo[y+h][i] {
+						break grow
+					}
+				}
+				h++
+			}
+
+			for j := y; j < y+h; j++ {
+				for i := x; i < x+w; i++ {
+					todo[j][i] = false
+				}
+			}
+			rects = append(rects, image.Rect(x, y, x+w, y+h).Add(bounds.Min))
+		}
+	}
+	return rects
+}
+
 // FindPath finds path between two coordinates on map
 func (m LevelMap) FindPath(start, dest Coord) []Coord {
 	var result []Coord
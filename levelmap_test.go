@@ -0,0 +1,45 @@
+// Use of this source code is subject to an MIT-style
+// licence which can be found in the LICENSE file.
+
+package main
+
+import (
+	"image"
+	"reflect"
+	"testing"
+)
+
+func TestMergeCells(t *testing.T) {
+	for _, data := range []struct {
+		Cells  []image.Point
+		Want   []image.Rectangle
+		Reason string
+	}{
+		{nil, nil, "nothing to merge"},
+		{
+			[]image.Point{{3, 4}},
+			[]image.Rectangle{image.Rect(3, 4, 4, 5)},
+			"a single cell stays a single cell",
+		},
+		{
+			[]image.Point{{0, 0}, {1, 0}, {2, 0}, {0, 1}, {1, 1}, {2, 1}},
+			[]image.Rectangle{image.Rect(0, 0, 3, 2)},
+			"a filled block becomes one rectangle",
+		},
+		{
+			[]image.Point{{0, 0}, {1, 0}, {0, 1}},
+			[]image.Rectangle{image.Rect(0, 0, 2, 1), image.Rect(0, 1, 1, 2)},
+			"an L-shape becomes two rectangles",
+		},
+		{
+			[]image.Point{{5, 5}, {7, 5}},
+			[]image.Rectangle{image.Rect(5, 5, 6, 6), image.Rect(7, 5, 8, 6)},
+			"cells with a gap between them stay apart",
+		},
+	} {
+		got := MergeCells(data.Cells)
+		if !reflect.DeepEqual(got, data.Want) {
+			t.Errorf("Merging %v gave %v, want %v, because: %s", data.Cells, got, data.Want, data.Reason)
+		}
+	}
+}
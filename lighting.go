@@ -0,0 +1,146 @@
+// Use of this source code is subject to an MIT-style
+// licence which can be found in the LICENSE file.
+
+package main
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// lightingEnabled is whether the lighting pass is drawn at all, turning it
+// off helps on weak hardware
+var lightingEnabled bool = true
+
+// How far (pixels) the flashlight reaches and how wide (radians either side
+// of where the player is facing) its cone is
+const (
+	flashlightLength = 220.0
+	flashlightWidth  = 0.45
+)
+
+// How far (pixels) a muzzle flash lights up and how long (ticks) it lasts
+const (
+	muzzleFlashRadius = 90.0
+	muzzleFlashTime   = 6
+)
+
+// How much the time of day moves every tick towards how far through the level
+// the player is, so it changes gradually after reaching each checkpoint
+const timeOfDaySpeed = 0.0005
+
+// TimeOfDay is what colour the light is at a point in the level, 0 being the
+// start and 1 the end
+type TimeOfDay struct {
+	Progress float64
+	Color    color.RGBA
+}
+
+// timesOfDay are the colours of the light through the level, from afternoon
+// sun to the middle of the night, ordered by progress
+var timesOfDay = []TimeOfDay{
+	{0, color.RGBA{0xff, 0xf8, 0xe6, 0xff}},   // afternoon
+	{0.5, color.RGBA{0xf2, 0xb3, 0x80, 0xff}}, // dusk
+	{0.8, color.RGBA{0x59, 0x59, 0x8c, 0xff}}, // nightfall
+	{1, color.RGBA{0x33, 0x38, 0x66, 0xff}},   // night
+}
+
+// Lighting darkens the world according to the time of day and lights it up
+// again with the player's flashlight and muzzle flashes
+type Lighting struct {
+	Shader    *ebiten.Shader
+	TimeOfDay float64 // How far through the day it is, from 0 to 1
+	Flash     int     // How many more ticks the muzzle flash is lit for
+	FlashPos  Coord   // Where the last muzzle flash was
+}
+
+// NewLighting creates a new lighting pass starting in the afternoon
+func NewLighting() *Lighting {
+	return &Lighting{Shader: loadShader("assets/shaders/lighting.kage")}
+}
+
+// MuzzleFlash lights up the area around the gun for a moment
+func (l *Lighting) MuzzleFlash(position Coord) {
+	l.Flash = muzzleFlashTime
+	l.FlashPos = position
+}
+
+// Update moves the time of day towards how far through the level the player is
+func (l *Lighting) Update(g *GameScreen) {
+	if l.Flash > 0 {
+		l.Flash--
+	}
+	target := 0.0
+	if g.Checkpoints > 0 {
+		target = float64(g.Checkpoint) / float64(g.Checkpoints)
+	}
+	if l.TimeOfDay < target {
+		l.TimeOfDay = math.Min(target, l.TimeOfDay+timeOfDaySpeed)
+	} else {
+		l.TimeOfDay = math.Max(target, l.TimeOfDay-timeOfDaySpeed)
+	}
+}
+
+// Ambient returns the colour of the light at the current time of day
+func (l *Lighting) Ambient() [3]float32 {
+	for i := 1; i < len(timesOfDay); i++ {
+		from, to := timesOfDay[i-1], timesOfDay[i]
+		if l.TimeOfDay > to.Progress && i < len(timesOfDay)-1 {
+			continue
+		}
+		t := (l.TimeOfDay - from.Progress) / (to.Progress - from.Progress)
+		t = math.Min(math.Max(t, 0), 1)
+		mix := func(a, b uint8) float32 {
+			return float32((float64(a) + (float64(b)-float64(a))*t) / 0xff)
+		}
+		return [3]float32{
+			mix(from.Color.R, to.Color.R),
+			mix(from.Color.G, to.Color.G),
+			mix(from.Color.B, to.Color.B),
+		}
+	}
+	return [3]float32{1, 1, 1}
+}
+
+// Draw darkens the camera surface, it should be drawn after the world and
+// before anything on the HUD
+func (l *Lighting) Draw(g *GameScreen) {
+	if !lightingEnabled {
+		return
+	}
+
+	ambient := l.Ambient()
+	// The flashlight is only worth turning on as it gets dark
+	brightness := 0.3*ambient[0] + 0.6*ambient[1] + 0.1*ambient[2]
+	flashlight := float32(math.Max(0, 1-float64(brightness))) * 1.2
+
+	player := g.Player.Position()
+	px, py := surfaceCoords(g.Camera, player.X, player.Y)
+	fx, fy := surfaceCoords(g.Camera, l.FlashPos.X, l.FlashPos.Y)
+
+	w, h := g.Camera.Surface.Size()
+	g.Camera.Surface.DrawRectShader(w, h, l.Shader, &ebiten.DrawRectShaderOptions{
+		// Multiply what's already drawn by the light
+		Blend: ebiten.Blend{
+			BlendFactorSourceRGB:        ebiten.BlendFactorDestinationColor,
+			BlendFactorSourceAlpha:      ebiten.BlendFactorZero,
+			BlendFactorDestinationRGB:   ebiten.BlendFactorZero,
+			BlendFactorDestinationAlpha: ebiten.BlendFactorOne,
+			BlendOperationRGB:           ebiten.BlendOperationAdd,
+			BlendOperationAlpha:         ebiten.BlendOperationAdd,
+		},
+		Uniforms: map[string]any{
+			"Ambient":       ambient[:],
+			"Player":        []float32{px, py},
+			"Angle":         float32(g.Player.Angle),
+			"Flashlight":    flashlight,
+			"ConeLength":    float32(flashlightLength),
+			"ConeWidth":     float32(flashlightWidth),
+			"Flash":         []float32{fx, fy},
+			"FlashRadius":   float32(muzzleFlashRadius),
+			"FlashStrength": float32(l.Flash) / muzzleFlashTime,
+		},
+	})
+}
@@ -23,14 +23,17 @@ type LoadingCounter *uint8
 
 var loadingWhat = []string{
 	"",
-	"map",
-	"music",
-	"sounds",
-	"sprites",
-	"entities",
-	"done",
+	"loading.map",
+	"loading.music",
+	"loading.sounds",
+	"loading.sprites",
+	"loading.entities",
+	"loading.done",
 }
 
+// credits are the names of everyone who made the game
+const credits = "Rowan Lindeque\nTristan Le Roux\nSiôn Le Roux\nPéter Kertész"
+
 // LoadingScreen is shown while all the assets are loading.
 // When loading is ready it switches to Intro screen
 type LoadingScreen struct {
@@ -69,19 +72,19 @@ func (s *LoadingScreen) Update() (GameState, error) {
 // Draw renders the start screen to the screen
 func (s *LoadingScreen) Draw(screen *ebiten.Image) {
 	var whatTxt string
-	if int(*s.Counter) < len(loadingWhat) {
-		whatTxt = loadingWhat[*s.Counter]
+	if int(*s.Counter) < len(loadingWhat) && loadingWhat[*s.Counter] != "" {
+		whatTxt = tr(loadingWhat[*s.Counter])
 	}
 	txt := s.textRenderer
 	txt.SetTarget(screen)
 	txt.SetColor(color.RGBA{0xff, 0xff, 0xff, s.alpha})
 	txt.Draw(
-		"An action adventure story by:\nRowan Lindeque\nTristan Le Roux\nSiôn Le Roux\nPéter Kertész",
+		tr("loading.credits")+"\n"+credits,
 		screen.Bounds().Dx()/2,
 		screen.Bounds().Dy()/2,
 	)
 	txt.Draw(
-		"Loading..."+whatTxt,
+		tr("loading.progress", whatTxt),
 		screen.Bounds().Dx()/2,
 		screen.Bounds().Dy()/8*7,
 	)
@@ -21,7 +21,15 @@ const sampleRate int = 44100 // assuming "normal" sample rate
 var context *audio.Context
 
 func main() {
-	const gameWidth, gameHeight = 320, 240
+	context = audio.NewContext(sampleRate)
+
+	ApplyConfigs()
+
+	gameWidth, gameHeight, err := parseResolution(resolution)
+	if err != nil {
+		log.Println("Error in resolution, using 4:3:", err)
+		gameWidth, gameHeight, _ = parseResolution("4:3")
+	}
 
 	ebiten.SetWindowSize(gameWidth*2, gameHeight*2)
 	ebiten.SetWindowTitle("eZcort mission")
@@ -29,10 +37,6 @@ func main() {
 	ebiten.SetWindowIcon([]image.Image{loadImage("assets/icon.png")})
 	ebiten.SetCursorMode(CursorMode) // set at build-time in cursor_{web,desktop}.go
 
-	context = audio.NewContext(sampleRate)
-
-	ApplyConfigs()
-
 	game := &Game{
 		Width:     gameWidth,
 		Height:    gameHeight,
@@ -51,7 +55,12 @@ func main() {
 
 	go NewGameScreen(game, loadingScreen.Counter)
 
-	if err := ebiten.RunGame(game); err != nil {
+	var run ebiten.Game = game
+	if pixelPerfect {
+		run = &PixelPerfectGame{game}
+	}
+
+	if err := ebiten.RunGame(run); err != nil {
 		log.Fatal(err)
 	}
 }
@@ -82,7 +91,8 @@ type Game struct {
 	Stat       *Stat
 }
 
-// Layout is hardcoded for now, may be made dynamic in future
+// Layout is the resolution picked in the settings, the screen is scaled up to
+// fit the window from there
 func (g *Game) Layout(outsideWidth int, outsideHeight int) (screenWidth int, screenHeight int) {
 	return g.Width, g.Height
 }
@@ -112,6 +122,7 @@ func (g *Game) Update() error {
 
 	if errors.Is(err, ErrorDoneLoading) {
 		if startingCheckpoint != 0 {
+			g.applyDifficulty()
 			g.Screens[gameRunning].(*GameScreen).Checkpoint = startingCheckpoint
 			g.State = gameOver
 		} else {
@@ -120,9 +131,15 @@ func (g *Game) Update() error {
 		return nil
 	}
 
+	if prevState == gameStart && g.State != gameStart {
+		g.applyDifficulty()
+	}
 	if prevState != gameRunning && g.State == gameRunning {
 		g.Screens[gameRunning].(*GameScreen).Start()
 	}
+	if prevState == gameRunning && g.State != gameRunning {
+		g.Screens[gameRunning].(*GameScreen).Stop()
+	}
 	if prevState != gameWon && g.State == gameWon {
 		g.Stat.GameWon = time.Now()
 	}
@@ -7,6 +7,7 @@ import (
 	"bytes"
 	"embed"
 	"encoding/json"
+	"image"
 	"image/png"
 	"io/ioutil"
 	"log"
@@ -118,6 +119,12 @@ func loadEntityImage(name string) *ebiten.Image {
 
 // Load an image from embedded FS into an ebiten Image object
 func loadImage(name string) *ebiten.Image {
+	return ebiten.NewImageFromImage(loadRawImage(name))
+}
+
+// Load an image from embedded FS without uploading it to the GPU, so its pixels
+// can still be changed
+func loadRawImage(name string) image.Image {
 	log.Printf("loading %s\n", name)
 
 	file, err := assets.Open(name)
@@ -135,7 +142,7 @@ func loadImage(name string) *ebiten.Image {
 		log.Fatalf("error empty data for sprite file %s\n", name)
 	}
 
-	return ebiten.NewImageFromImage(raw)
+	return raw
 }
 
 // Load an project from embedded FS into an LDtk Project object
@@ -162,6 +169,54 @@ func loadMaps(name string) *ldtkgo.Project {
 	return maps
 }
 
+// LayerDefs is what the layer definitions in an LDtk project say that LDtk-Go
+// doesn't tell us
+type LayerDefs struct {
+	Terrain  IntGridValues    // Terrain each IntGrid value represents by layer
+	Parallax map[string]Coord // Parallax factors of each layer, parallaxScaling is ignored
+}
+
+// Load the layer definitions from an LDtk project in the embedded FS, because
+// LDtk-Go doesn't tell which layer IntGrid values belong to or how layers
+// scroll
+func loadLayerDefs(name string) LayerDefs {
+	log.Printf("loading layer definitions from %s\n", name)
+
+	file, err := assets.Open(name)
+	if err != nil {
+		log.Fatalf("error opening file %s: %v\n", name, err)
+	}
+	defer file.Close()
+
+	var project struct {
+		Defs struct {
+			Layers []struct {
+				Identifier      string  `json:"identifier"`
+				ParallaxFactorX float64 `json:"parallaxFactorX"`
+				ParallaxFactorY float64 `json:"parallaxFactorY"`
+				IntGridValues   []struct {
+					Value      int    `json:"value"`
+					Identifier string `json:"identifier"`
+					Color      string `json:"color"`
+				} `json:"intGridValues"`
+			} `json:"layers"`
+		} `json:"defs"`
+	}
+	if err := json.NewDecoder(file).Decode(&project); err != nil {
+		log.Fatalf("error parsing file %s as LDtk Project: %v\n", name, err)
+	}
+
+	defs := LayerDefs{Terrain: IntGridValues{}, Parallax: map[string]Coord{}}
+	for _, layer := range project.Defs.Layers {
+		defs.Parallax[layer.Identifier] = Coord{layer.ParallaxFactorX, layer.ParallaxFactorY}
+		defs.Terrain[layer.Identifier] = map[int]*Terrain{}
+		for _, v := range layer.IntGridValues {
+			defs.Terrain[layer.Identifier][v.Value] = ParseTerrain(v.Identifier, v.Color)
+		}
+	}
+	return defs
+}
+
 // SoundType is a unique identifier to reference sound by name
 type SoundType uint8
 
@@ -183,6 +238,7 @@ const (
 	soundBigZombieDeath1
 	soundBigZombieScream
 	soundBigZombieDeath2
+	soundFootstep
 )
 
 const (
@@ -197,7 +253,10 @@ const (
 type Sound struct {
 	Audio      []SoundData
 	LastPlayed *audio.Player
+	LastIndex  int // Which of the variants was played last
 	Volume     float64
+	Start      time.Duration   // Where to start playing from, e.g. to skip silence
+	Subtitles  []SubtitleTrack // Subtitles for each variant, if it's a voice line
 }
 
 // AddSound adds one new sound to the soundType
@@ -217,6 +276,7 @@ func (s *Sound) AddSound(f string, sampleRate int, context *audio.Context, v ...
 		}
 
 		s.Audio = append(s.Audio, loadSoundFile(filename, sampleRate))
+		s.Subtitles = append(s.Subtitles, subtitleTrack(filename))
 	}
 }
 
@@ -248,13 +308,33 @@ func (s *Sound) PlayVariant(i int) {
 	}
 	sound := NewSoundPlayer(s.Audio[i])
 	s.LastPlayed = sound
+	s.LastIndex = i
 	sound.SetVolume(s.Volume)
+	if s.Start > 0 {
+		sound.SetPosition(s.Start)
+	}
 	sound.Play()
 }
 
+// Restart plays the sound again from the start, reusing the audio player from
+// last time so that it never plays over itself, e.g. for footsteps
+func (s *Sound) Restart() {
+	if s.LastPlayed == nil {
+		s.Play()
+		return
+	}
+	s.LastPlayed.SetVolume(s.Volume)
+	if err := s.LastPlayed.SetPosition(s.Start); err != nil {
+		log.Printf("error rewinding sound: %v\n", err)
+	}
+	s.LastPlayed.Play()
+}
+
 // Pause pauses the audio being played
 func (s *Sound) Pause() {
-	s.LastPlayed.Pause()
+	if s.LastPlayed != nil {
+		s.LastPlayed.Pause()
+	}
 }
 
 // IsPlaying returns if the sound is playing
@@ -267,7 +347,10 @@ type Sounds []*Sound
 
 func (s *Sound) Shuffle() {
 	rand.Seed(time.Now().UnixNano())
-	rand.Shuffle(len(s.Audio), func(i, j int) { s.Audio[i], s.Audio[j] = s.Audio[j], s.Audio[i] })
+	rand.Shuffle(len(s.Audio), func(i, j int) {
+		s.Audio[i], s.Audio[j] = s.Audio[j], s.Audio[i]
+		s.Subtitles[i], s.Subtitles[j] = s.Subtitles[j], s.Subtitles[i]
+	})
 }
 
 // MusicLoop is an audio player that infinitely loops back to its start
@@ -332,6 +415,22 @@ func NewSoundPlayer(data SoundData) *audio.Player {
 	return audioPlayer
 }
 
+// loadFootsteps loads the footstep sound for every kind of terrain that has its
+// own, by the sound's name
+func loadFootsteps(values IntGridValues) map[string]*Sound {
+	footsteps := map[string]*Sound{}
+	for _, layer := range values {
+		for _, terrain := range layer {
+			if terrain.Sound == "" || footsteps[terrain.Sound] != nil {
+				continue
+			}
+			footsteps[terrain.Sound] = &Sound{}
+			footsteps[terrain.Sound].AddSound("assets/sfx/Footstep-"+terrain.Sound, sampleRate, context)
+		}
+	}
+	return footsteps
+}
+
 // SoundData is bytes returned from a sound file
 type SoundData []byte
 
@@ -353,7 +452,51 @@ func loadSoundFile(name string, sampleRate int) SoundData {
 	return data
 }
 
+// Load a Kage shader from embedded FS and compile it
+func loadShader(name string) *ebiten.Shader {
+	log.Printf("loading %s\n", name)
+
+	src, err := assets.ReadFile(name)
+	if err != nil {
+		log.Fatalf("error reading file %s: %v\n", name, err)
+	}
+
+	shader, err := ebiten.NewShader(src)
+	if err != nil {
+		log.Fatalf("error compiling shader %s: %v\n", name, err)
+	}
+
+	return shader
+}
+
+// Load a font from embedded FS, or the font the chosen language uses in its
+// place, falling back to another font if it's missing letters the language
+// needs
 func loadFont(name string) *etxt.Font {
+	lang := currentLanguage()
+	if lang == nil {
+		return parseFont(name)
+	}
+	if replacement, ok := lang.Fonts[name]; ok {
+		name = replacement
+	}
+	font := parseFont(name)
+	missing := lang.MissingRunes(font)
+	if len(missing) == 0 {
+		return font
+	}
+	for _, fallback := range lang.Fallback {
+		f := parseFont(fallback)
+		if len(lang.MissingRunes(f)) == 0 {
+			log.Printf("font %s is missing %q, using %s instead", name, string(missing), fallback)
+			return f
+		}
+	}
+	log.Printf("font %s is missing %q and there's no fallback for it", name, string(missing))
+	return font
+}
+
+func parseFont(name string) *etxt.Font {
 	font, fname, err := etxt.ParseEmbedFontFrom(name, assets)
 	if err != nil {
 		log.Fatalf("error parsing font %s: %v", name, err)
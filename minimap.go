@@ -0,0 +1,134 @@
+// Use of this source code is subject to an MIT-style
+// licence which can be found in the LICENSE file.
+
+package main
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/solarlune/ldtkgo"
+)
+
+// minimapScale is how many screen pixels each map cell takes up on the minimap
+var minimapScale float64 = 0.5
+
+// How far (pixels) from the player zombies show up on the minimap
+const minimapBlipRange = 300.0
+
+// Colours of things on the minimap
+var (
+	colorMinimapFrame      = color.RGBA{0x00, 0x00, 0x00, 0x90}
+	colorMinimapPlayer     = color.RGBA{0xff, 0xff, 0xff, 0xff}
+	colorMinimapDog        = color.RGBA{0xf0, 0xc0, 0x40, 0xff}
+	colorMinimapZombie     = color.RGBA{0xe0, 0x30, 0x30, 0xff}
+	colorMinimapCheckpoint = color.RGBA{0x40, 0xa0, 0xff, 0xff}
+	colorMinimapWalked     = color.RGBA{0xf0, 0xc0, 0x40, 0xc0}
+)
+
+// Minimap is a small map of the level in the corner of the screen showing
+// where everyone is
+type Minimap struct {
+	Visible  bool
+	Image    *ebiten.Image // The level with one pixel per map cell
+	GridSize float64       // How many pixels on the level one pixel on the minimap is
+}
+
+// NewMinimap draws a minimap of the level from its IntGrid layers, colouring
+// each cell by its terrain
+func NewMinimap(level *ldtkgo.Level, values IntGridValues) *Minimap {
+	m := &Minimap{
+		Visible:  true,
+		GridSize: gridSize,
+	}
+	m.Image = ebiten.NewImage(
+		int(math.Ceil(float64(level.Width)/m.GridSize)),
+		int(math.Ceil(float64(level.Height)/m.GridSize)),
+	)
+	m.Image.Fill(level.BGColor)
+
+	// LDtk lists layers from the top down, so draw them the other way round
+	for i := len(level.Layers) - 1; i >= 0; i-- {
+		layer := level.Layers[i]
+		if layer.Type != ldtkgo.LayerTypeIntGrid {
+			continue
+		}
+		for _, intData := range layer.IntGrid {
+			terrain, ok := values.TerrainAt(layer.Identifier, intData.Value)
+			if !ok || terrain.Map.A == 0 {
+				continue
+			}
+			x := float32(float64(intData.Position[0]+layer.OffsetX) / m.GridSize)
+			y := float32(float64(intData.Position[1]+layer.OffsetY) / m.GridSize)
+			size := float32(float64(layer.GridSize) / m.GridSize)
+			vector.DrawFilledRect(m.Image, x, y, size, size, terrain.Map, false)
+		}
+	}
+
+	return m
+}
+
+// Toggle shows or hides the minimap
+func (m *Minimap) Toggle() {
+	m.Visible = !m.Visible
+}
+
+// Draw draws the minimap in the top-left corner of the screen, this is intended
+// to be drawn onto the screen along with the HUD
+func (m *Minimap) Draw(g *GameScreen, screen *ebiten.Image) {
+	if !m.Visible {
+		return
+	}
+
+	w := float32(float64(m.Image.Bounds().Dx()) * minimapScale)
+	h := float32(float64(m.Image.Bounds().Dy()) * minimapScale)
+	left, top := float32(hudPadding), float32(hudPadding)
+	vector.DrawFilledRect(screen, left-1, top-1, w+2, h+2, colorMinimapFrame, false)
+
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Scale(minimapScale, minimapScale)
+	op.GeoM.Translate(float64(left), float64(top))
+	if minimapScale < 1 {
+		op.Filter = ebiten.FilterLinear
+	}
+	screen.DrawImage(m.Image, op)
+
+	// toMap converts a position on the level to a position on the minimap
+	toMap := func(c Coord) (float32, float32) {
+		return left + float32(c.X/m.GridSize*minimapScale),
+			top + float32(c.Y/m.GridSize*minimapScale)
+	}
+	blip := func(c Coord, size float32, clr color.Color) {
+		x, y := toMap(c)
+		vector.DrawFilledRect(screen, x-size/2, y-size/2, size, size, clr, false)
+	}
+
+	// How far the dog has come along its route
+	if path := g.Dog.MainPath; path != nil {
+		for i := 1; i < len(path.Points) && i <= path.NextPoint; i++ {
+			x1, y1 := toMap(path.Points[i-1])
+			x2, y2 := toMap(path.Points[i])
+			vector.StrokeLine(screen, x1, y1, x2, y2, 1, colorMinimapWalked, false)
+		}
+	}
+
+	for n, c := range g.CheckpointPositions {
+		if n <= g.Checkpoint {
+			blip(c, 3, colorMinimapCheckpoint)
+		}
+	}
+
+	player := g.Player.Position()
+	for _, z := range g.Zombies {
+		// Only zombies that aren't behind walls, even off the screen
+		pos := z.Position()
+		if math.Hypot(pos.X-player.X, pos.Y-player.Y) < minimapBlipRange && g.Vision.InLineOfSight(g, *pos) {
+			blip(*pos, 1, colorMinimapZombie)
+		}
+	}
+
+	blip(*g.Dog.Position(), 2, colorMinimapDog)
+	blip(*player, 2, colorMinimapPlayer)
+}
@@ -0,0 +1,204 @@
+// Use of this source code is subject to an MIT-style
+// licence which can be found in the LICENSE file.
+
+package main
+
+import (
+	"image/color"
+	"math"
+	"math/rand"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	camera "github.com/melonfunction/ebiten-camera"
+)
+
+// How long (ticks) blood stains stay on the ground before they are gone
+const decalLifetime = 20 * 60
+
+// The most blood stains that can be on the ground at the same time, the oldest
+// ones are removed first when there are too many
+const maxDecals = 400
+
+// How much particles slow down each tick
+const particleFriction = 0.85
+
+var (
+	colorMuzzleFlash = color.RGBA{0xff, 0xe0, 0x80, 0xff}
+	colorTracer      = color.RGBA{0xff, 0xf0, 0xc0, 0xc0}
+	colorBlood       = color.RGBA{0x8a, 0x10, 0x10, 0xff}
+	colorBloodDecal  = color.RGBA{0x5a, 0x08, 0x08, 0xc0}
+	colorDust        = color.RGBA{0xc8, 0xb4, 0x8c, 0xc0}
+)
+
+// Particle is a single small square flying about, like a drop of blood
+type Particle struct {
+	Position Coord      // Where the particle is in the world
+	Velocity Coord      // How far the particle moves per tick
+	Size     float32    // Width and height of the particle
+	Life     int        // How many more ticks the particle is shown for
+	MaxLife  int        // How many ticks the particle lived for in total
+	Color    color.RGBA // Colour of the particle at full strength
+	Decal    bool       // Whether it leaves a stain on the ground when it's gone
+}
+
+// Tracer is a short-lived line showing the path of a bullet
+type Tracer struct {
+	From, To Coord
+	Life     int
+}
+
+// Particles is a lightweight particle system for visual effects like blood
+// and gunfire, it also keeps track of stains left on the ground by particles
+type Particles struct {
+	Particles []*Particle
+	Tracers   []*Tracer
+	Decals    []*Particle
+}
+
+// NewParticles creates an empty particle system
+func NewParticles() *Particles {
+	return &Particles{}
+}
+
+// Clear removes all particles, tracers and stains, e.g. when respawning
+func (ps *Particles) Clear() {
+	ps.Particles = nil
+	ps.Tracers = nil
+	ps.Decals = nil
+}
+
+// Update moves particles along and removes the ones that have run their course
+func (ps *Particles) Update() {
+	particles := ps.Particles[:0]
+	for _, p := range ps.Particles {
+		p.Life--
+		if p.Life <= 0 {
+			if p.Decal {
+				ps.addDecal(p)
+			}
+			continue
+		}
+		p.Position.X += p.Velocity.X
+		p.Position.Y += p.Velocity.Y
+		p.Velocity.X *= particleFriction
+		p.Velocity.Y *= particleFriction
+		particles = append(particles, p)
+	}
+	ps.Particles = particles
+
+	tracers := ps.Tracers[:0]
+	for _, t := range ps.Tracers {
+		t.Life--
+		if t.Life > 0 {
+			tracers = append(tracers, t)
+		}
+	}
+	ps.Tracers = tracers
+
+	decals := ps.Decals[:0]
+	for _, d := range ps.Decals {
+		d.Life--
+		if d.Life > 0 {
+			decals = append(decals, d)
+		}
+	}
+	ps.Decals = decals
+}
+
+// addDecal leaves a stain on the ground where the particle landed
+func (ps *Particles) addDecal(p *Particle) {
+	if len(ps.Decals) >= maxDecals {
+		ps.Decals = ps.Decals[1:]
+	}
+	ps.Decals = append(ps.Decals, &Particle{
+		Position: p.Position,
+		Size:     p.Size + 1,
+		Life:     decalLifetime,
+		MaxLife:  decalLifetime,
+		Color:    colorBloodDecal,
+	})
+}
+
+// DrawDecals draws stains on the ground, this is intended to be drawn right
+// after the background so that everything else is drawn over it
+func (ps *Particles) DrawDecals(g *GameScreen) {
+	for _, d := range ps.Decals {
+		drawParticle(g.Camera, d)
+	}
+}
+
+// Draw draws the particles and tracers flying about, this is intended to be
+// drawn after the entities but before the foreground
+func (ps *Particles) Draw(g *GameScreen) {
+	for _, t := range ps.Tracers {
+		x1, y1 := surfaceCoords(g.Camera, t.From.X, t.From.Y)
+		x2, y2 := surfaceCoords(g.Camera, t.To.X, t.To.Y)
+		vector.StrokeLine(g.Camera.Surface, x1, y1, x2, y2, 1, colorTracer, false)
+	}
+	for _, p := range ps.Particles {
+		drawParticle(g.Camera, p)
+	}
+}
+
+// drawParticle draws a single particle to the camera surface, fading it out
+// as it gets to the end of its life
+func drawParticle(cam *camera.Camera, p *Particle) {
+	c := p.Color
+	c.A = uint8(float64(c.A) * math.Min(1, float64(p.Life)/float64(p.MaxLife)*2))
+	x, y := surfaceCoords(cam, p.Position.X, p.Position.Y)
+	vector.DrawFilledRect(cam.Surface, x-p.Size/2, y-p.Size/2, p.Size, p.Size, c, false)
+}
+
+// surfaceCoords converts world coordinates to coordinates on the camera surface
+func surfaceCoords(cam *camera.Camera, x, y float64) (float32, float32) {
+	op := cam.GetTranslation(&ebiten.DrawImageOptions{}, x, y)
+	sx, sy := op.GeoM.Apply(0, 0)
+	return float32(sx), float32(sy)
+}
+
+// emit adds count particles spreading out from the position in the direction
+// of the angle, within the given spread angle either side
+func (ps *Particles) emit(position Coord, angle, spread, speed float64, count, life int, size float32, c color.RGBA, decal bool) {
+	for i := 0; i < count; i++ {
+		a := angle + (rand.Float64()*2-1)*spread
+		s := speed * (0.5 + rand.Float64())
+		l := life/2 + rand.Intn(life/2+1)
+		ps.Particles = append(ps.Particles, &Particle{
+			Position: position,
+			Velocity: Coord{X: math.Cos(a) * s, Y: math.Sin(a) * s},
+			Size:     size,
+			Life:     l,
+			MaxLife:  l,
+			Color:    c,
+			Decal:    decal,
+		})
+	}
+}
+
+// EmitMuzzleFlash shows a bright flash at the end of the gun barrel
+func (ps *Particles) EmitMuzzleFlash(position Coord, angle float64) {
+	ps.emit(position, angle, 0.4, 1.5, 6, 4, 2, colorMuzzleFlash, false)
+}
+
+// EmitTracer shows a brief line along the path of a bullet
+func (ps *Particles) EmitTracer(from, to Coord) {
+	ps.Tracers = append(ps.Tracers, &Tracer{From: from, To: to, Life: 3})
+}
+
+// EmitBlood sprays blood in the direction of the angle, leaving stains where
+// the drops land
+func (ps *Particles) EmitBlood(position Coord, angle float64, count int) {
+	ps.emit(position, angle, 0.6, 2, count, 20, 1, colorBlood, true)
+}
+
+// EmitKick shows a few particles kicked up behind something walking in the
+// direction of the angle
+func (ps *Particles) EmitKick(position Coord, angle float64, c color.RGBA) {
+	ps.emit(position, angle+math.Pi, 0.6, 0.5, 3, 16, 1, c, false)
+}
+
+// EmitDust shows a puff of dust bouncing back from where a bullet hit a wall
+func (ps *Particles) EmitDust(position Coord, angle float64) {
+	ps.emit(position, angle+math.Pi, 0.8, 0.8, 8, 24, 2, colorDust, false)
+}
@@ -6,8 +6,11 @@ package main
 import (
 	"image"
 	"math"
+	"math/rand"
+	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
 	"github.com/solarlune/resolv"
 )
 
@@ -23,8 +26,26 @@ var playerSpeedFactorSideways float64 = 0.6
 
 var playerSpeedFactorSprint float64 = 2.4
 
+// amount to change speed by when the player is aiming down the sights
+var playerSpeedFactorAiming float64 = 0.5
+
 var playerAmmoClipMax int = 7
 
+// The footstep sound has some silence before the steps start
+const footstepStart = 660 * time.Millisecond
+
+// How loud footsteps are on plain ground
+const footstepVolume = 0.7
+
+// how far you can shoot with the gun normally and when aiming down the sights
+var playerRange float64 = 200
+var playerRangeAiming float64 = 300
+
+// how far (radians) bullets can stray either side of where the player is
+// pointing when shooting from the hip and when aiming down the sights
+var playerSpreadHip float64 = 0.06
+var playerSpreadAiming float64 = 0.01
+
 // states of the player
 // It would be great to map them to the frameTag.Name from JSON
 type playerState int
@@ -47,10 +68,13 @@ type Player struct {
 	State     playerState    // The current animation state
 	PrevState playerState    // The previous animation state
 	Sprinting bool           // Whether the player is sprinting or not
+	SprintOn  bool           // Whether sprinting is switched on, when sprint is a toggle
+	Aiming    bool           // Whether the player is aiming down the sights
 	Sprite    *SpriteSheet   // Used for player animations
 	Range     float64        // How far you can shoot with the gun
 	Ammo      int            // How many shots you have left in the gun
 	TempSpeed float64        // Temporary speed multiplier
+	Terrain   *Terrain       // The terrain the player is walking on
 }
 
 // NewPlayer constructs a new Player object at the provided location and size
@@ -75,7 +99,7 @@ func NewPlayer(position []int, sprites *SpriteSheet) *Player {
 		Object:    object,
 		Angle:     0,
 		Sprite:    sprites,
-		Range:     200,
+		Range:     playerRange,
 		Ammo:      playerAmmoClipMax,
 		TempSpeed: 1,
 	}
@@ -94,9 +118,18 @@ func (p *Player) Update(g *GameScreen) {
 	p.PrevState = p.State
 	p.Sprinting = false
 
+	// Aiming down the sights lets you shoot further
+	p.Range = playerRange
+	if p.Aiming {
+		p.Range = playerRangeAiming
+	}
+
 	if p.State == playerIdle || p.State == playerWalking {
 		p.State = playerIdle
 		p.handleControls()
+		if p.State == playerWalking {
+			p.Terrain.KickUp(g, *p.Position(), p.Angle)
+		}
 	}
 
 	if p.Frame == p.Sprite.Meta.FrameTags[p.State].To {
@@ -104,16 +137,55 @@ func (p *Player) Update(g *GameScreen) {
 	}
 
 	// Player gun rotation
-	cx, cy := g.Camera.GetCursorCoords()
+	cx, cy := g.Camera.GetWorldCoords(cursorPosition())
 	adjacent := float64(cx) - p.Object.Position.X
 	opposite := float64(cy) - p.Object.Position.Y
 	p.Angle = math.Atan2(opposite, adjacent)
 
+	prevFrame := p.Frame
 	p.Frame = Animate(p.Frame, g.Tick, p.Sprite.Meta.FrameTags[p.State])
+	if p.Frame != prevFrame && p.State == playerWalking {
+		p.footsteps(g)
+	}
+	if p.State != playerWalking && p.PrevState == playerWalking {
+		stopFootsteps(g, nil)
+	}
+
 	p.Object.Shape.SetRotation(-p.Angle)
 	p.Object.Update()
 }
 
+// footsteps plays footstep sounds in time with the walking animation, there
+// are two steps in the sound so it's played once for every walk cycle, twice as
+// often when sprinting, it starts over each time instead of playing over itself
+func (p *Player) footsteps(g *GameScreen) {
+	walking := p.Sprite.Meta.FrameTags[playerWalking]
+	halfway := walking.From + (walking.To-walking.From+1)/2
+	if p.Frame == walking.From || (p.Sprinting && p.Frame == halfway) {
+		// Some terrain has its own footstep sound
+		sound := g.Sounds[soundFootstep]
+		if s, ok := g.Footsteps[p.Terrain.FootstepSound()]; ok {
+			sound = s
+		}
+		stopFootsteps(g, sound)
+		sound.SetVolume(footstepVolume * p.Terrain.FootstepVolume())
+		sound.Restart()
+	}
+}
+
+// stopFootsteps stops all the footstep sounds except for one, which can be nil
+// to stop them all
+func stopFootsteps(g *GameScreen, except *Sound) {
+	if g.Sounds[soundFootstep] != except {
+		g.Sounds[soundFootstep].Pause()
+	}
+	for _, s := range g.Footsteps {
+		if s != except {
+			s.Pause()
+		}
+	}
+}
+
 // Animation-trigged state changes
 func (p *Player) animationBasedStateChanges(g *GameScreen) {
 	switch p.State {
@@ -151,7 +223,7 @@ func (p *Player) MoveRight() {
 // MoveForward moves the player forward towards the pointer
 func (p *Player) MoveForward() {
 	speed := playerSpeed
-	if p.Sprinting && p.TempSpeed >= 1 {
+	if p.Sprinting && p.TempSpeed >= 1 && !p.Aiming {
 		speed = speed * playerSpeedFactorSprint
 	} else {
 		speed = speed * p.TempSpeed
@@ -175,14 +247,15 @@ func (p *Player) MoveBackward() {
 func (p *Player) move(dx, dy float64) {
 	p.State = playerWalking
 
-	// Collision detection and response between sand trap and player
-	p.TempSpeed = 1
-	if collision := p.Object.Check(0, 0, tagSandTrap); collision != nil {
-		if p.Object.Shape.Intersection(0, 0, collision.Objects[0].Shape) != nil {
-			p.TempSpeed = sandTrapSpeedMultiplier
-		}
+	// You have to move carefully when aiming down the sights
+	if p.Aiming {
+		dx, dy = dx*playerSpeedFactorAiming, dy*playerSpeedFactorAiming
 	}
 
+	// Terrain like sand traps slows the player down
+	p.Terrain = TerrainUnder(p.Object, entityPlayer)
+	p.TempSpeed = p.Terrain.SpeedFor(entityPlayer)
+
 	if collision := p.Object.Check(dx, 0, tagWall, tagDog); collision != nil {
 		for _, o := range collision.Objects {
 			if p.Object.Shape.Intersection(dx, 0, o.Shape) != nil {
@@ -234,7 +307,13 @@ func (p *Player) Draw(g *GameScreen) {
 }
 
 func (p *Player) handleControls() {
-	if ebiten.IsKeyPressed(ebiten.KeyShift) {
+	// Shift is either held to sprint or pressed to switch sprinting on and off
+	if toggleHoldActions {
+		if inpututil.IsKeyJustPressed(ebiten.KeyShift) {
+			p.SprintOn = !p.SprintOn
+		}
+		p.Sprinting = p.SprintOn
+	} else if ebiten.IsKeyPressed(ebiten.KeyShift) {
 		p.Sprinting = true
 	}
 	if ebiten.IsKeyPressed(ebiten.KeyW) {
@@ -258,3 +337,43 @@ func (p *Player) Position() *Coord {
 		Y: p.Object.Position.Y,
 	}
 }
+
+// Depth returns where the Player's feet are for sorting the draw order
+func (p *Player) Depth() float64 {
+	return p.Object.Position.Y + p.Object.Size.Y/2
+}
+
+// AimRay returns the start and end points of the line of fire from the
+// Player's gun, reaching as far as the gun's range
+func (p *Player) AimRay() (resolv.Vector, resolv.Vector) {
+	return p.ray(p.Angle)
+}
+
+// ShotRay returns the start and end points of the path of a bullet, which
+// strays randomly from where the Player is aiming depending on the accuracy
+func (p *Player) ShotRay() (resolv.Vector, resolv.Vector) {
+	spread := playerSpreadHip
+	if p.Aiming {
+		spread = playerSpreadAiming
+	}
+	return p.ray(p.Angle + (rand.Float64()*2-1)*spread)
+}
+
+// ray returns the start and end points of a line from the Player at the angle,
+// reaching as far as the gun's range
+func (p *Player) ray(angle float64) (resolv.Vector, resolv.Vector) {
+	start := resolv.NewVector(p.Object.Position.X, p.Object.Position.Y)
+	direction := resolv.NewVector(math.Cos(angle), math.Sin(angle))
+	return start, start.Add(direction.Scale(p.Range))
+}
+
+// MuzzlePosition returns the coordinates of the end of the gun barrel
+func (p *Player) MuzzlePosition() Coord {
+	// the gun barrel sticks out about 10px in front of the player's head
+	const barrelLength float64 = 10
+
+	return Coord{
+		X: p.Object.Position.X + math.Cos(p.Angle)*barrelLength,
+		Y: p.Object.Position.Y + math.Sin(p.Angle)*barrelLength,
+	}
+}
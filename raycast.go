@@ -0,0 +1,81 @@
+// Use of this source code is subject to an MIT-style
+// licence which can be found in the LICENSE file.
+
+package main
+
+import (
+	"math"
+
+	"github.com/solarlune/resolv"
+)
+
+// RayHit describes where a ray cast through the collision space first hit
+// something
+type RayHit struct {
+	Object   *resolv.Object // The object that was hit
+	Point    resolv.Vector  // World coordinates of the point of impact
+	Distance float64        // Distance from the start of the ray to the impact
+}
+
+// Raycast casts a ray from start to end through the space and returns the
+// nearest object with one of the given tags whose shape the ray intersects, or
+// nil if nothing was hit. Objects without a ConvexPolygon shape are ignored.
+func Raycast(space *resolv.Space, start, end resolv.Vector, tags ...string) *RayHit {
+	sx, sy := space.WorldToSpace(math.Min(start.X, end.X), math.Min(start.Y, end.Y))
+	ex, ey := space.WorldToSpace(math.Max(start.X, end.X), math.Max(start.Y, end.Y))
+
+	var nearest *RayHit
+	checked := map[*resolv.Object]bool{}
+	for _, o := range space.CheckCells(sx, sy, ex-sx+1, ey-sy+1, tags...) {
+		// Big objects are in many cells but only need checking once
+		if checked[o] {
+			continue
+		}
+		checked[o] = true
+
+		polygon, ok := o.Shape.(*resolv.ConvexPolygon)
+		if !ok {
+			continue
+		}
+		point, distance, ok := rayPolygonIntersection(start, end, polygon.Transformed())
+		if ok && (nearest == nil || distance < nearest.Distance) {
+			nearest = &RayHit{Object: o, Point: point, Distance: distance}
+		}
+	}
+	return nearest
+}
+
+// rayPolygonIntersection finds the point closest to start where the segment
+// from start to end crosses an edge of the closed polygon given by verts
+func rayPolygonIntersection(start, end resolv.Vector, verts []resolv.Vector) (resolv.Vector, float64, bool) {
+	nearestT := math.Inf(1)
+	for i := range verts {
+		a, b := verts[i], verts[(i+1)%len(verts)]
+		if t, ok := segmentIntersection(start, end, a, b); ok && t < nearestT {
+			nearestT = t
+		}
+	}
+	if math.IsInf(nearestT, 1) {
+		return resolv.Vector{}, 0, false
+	}
+	delta := end.Sub(start)
+	return start.Add(delta.Scale(nearestT)), delta.Magnitude() * nearestT, true
+}
+
+// segmentIntersection returns how far along the segment p1→p2 (from 0 to 1) it
+// crosses the segment q1→q2, and whether they cross at all
+func segmentIntersection(p1, p2, q1, q2 resolv.Vector) (float64, bool) {
+	r := p2.Sub(p1)
+	s := q2.Sub(q1)
+	denominator := r.X*s.Y - r.Y*s.X
+	if denominator == 0 {
+		return 0, false // parallel or collinear, treat as a miss
+	}
+	qp := q1.Sub(p1)
+	t := (qp.X*s.Y - qp.Y*s.X) / denominator
+	u := (qp.X*r.Y - qp.Y*r.X) / denominator
+	if t < 0 || t > 1 || u < 0 || u > 1 {
+		return 0, false
+	}
+	return t, true
+}
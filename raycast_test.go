@@ -0,0 +1,77 @@
+// Use of this source code is subject to an MIT-style
+// licence which can be found in the LICENSE file.
+
+package main
+
+import (
+	"math"
+	"testing"
+
+	"github.com/solarlune/resolv"
+)
+
+func TestRayPolygonIntersection(t *testing.T) {
+	square := resolv.NewRectangle(10, -5, 10, 10).Transformed() // x 10→20, y -5→5
+	for _, data := range []struct {
+		Start, End   resolv.Vector
+		Hit          bool
+		WantX, WantY float64
+		Reason       string
+	}{
+		{resolv.NewVector(0, 0), resolv.NewVector(30, 0), true, 10, 0, "hits the nearest edge"},
+		{resolv.NewVector(30, 0), resolv.NewVector(0, 0), true, 20, 0, "hits the nearest edge from the other side"},
+		{resolv.NewVector(0, 0), resolv.NewVector(5, 0), false, 0, 0, "stops short of the shape"},
+		{resolv.NewVector(0, 10), resolv.NewVector(30, 10), false, 0, 0, "passes beside the shape"},
+		{resolv.NewVector(0, -10), resolv.NewVector(30, 20), true, 10, 0, "enters diagonally through the left edge"},
+		{resolv.NewVector(15, -20), resolv.NewVector(15, 20), true, 15, -5, "enters from above through the top edge"},
+	} {
+		point, _, ok := rayPolygonIntersection(data.Start, data.End, square)
+		if ok != data.Hit {
+			t.Errorf("Ray %v→%v hit was %t, want %t, because: %s", data.Start, data.End, ok, data.Hit, data.Reason)
+			continue
+		}
+		if ok && (math.Abs(point.X-data.WantX) > 1e-9 || math.Abs(point.Y-data.WantY) > 1e-9) {
+			t.Errorf("Ray %v→%v hit at %v, want (%g, %g), because: %s", data.Start, data.End, point, data.WantX, data.WantY, data.Reason)
+		}
+	}
+}
+
+func TestRaycast(t *testing.T) {
+	space := resolv.NewSpace(160, 160, 16, 16)
+	for _, r := range []struct{ X, Y, W, H float64 }{{64, 32, 32, 32}, {112, 32, 32, 32}} {
+		wall := resolv.NewObject(r.X, r.Y, r.W, r.H, "wall")
+		wall.SetShape(resolv.NewRectangle(r.X, r.Y, r.W, r.H))
+		space.Add(wall)
+	}
+	// A boss whose body reaches into cells above and left of its position
+	boss := newMobBody(32, 32)
+	space.Add(boss)
+	centerObject(boss, resolv.NewVector(88, 120)) // x 72→104, y 104→136
+	for _, data := range []struct {
+		Start, End   resolv.Vector
+		Tag          string
+		Hit          bool
+		WantX, WantY float64
+		Reason       string
+	}{
+		{resolv.NewVector(0, 40), resolv.NewVector(150, 40), "wall", true, 64, 40, "a horizontal ray hits the nearest wall"},
+		{resolv.NewVector(150, 40), resolv.NewVector(0, 40), "wall", true, 144, 40, "a horizontal ray hits the nearest wall from the other side"},
+		{resolv.NewVector(70, 0), resolv.NewVector(70, 100), "wall", true, 70, 32, "a vertical ray hits the wall"},
+		{resolv.NewVector(70, 100), resolv.NewVector(70, 0), "wall", true, 70, 64, "a vertical ray hits the wall from below"},
+		{resolv.NewVector(0, 10), resolv.NewVector(150, 10), "wall", false, 0, 0, "a horizontal ray passes above the walls"},
+		{resolv.NewVector(0, 40), resolv.NewVector(50, 40), "wall", false, 0, 0, "a ray stops short of the wall"},
+		{resolv.NewVector(0, 40), resolv.NewVector(150, 40), tagMobBody, false, 0, 0, "walls aren't hit when looking for something else"},
+		{resolv.NewVector(0, 106), resolv.NewVector(150, 106), tagMobBody, true, 72, 106, "a ray hits the edge of a mob's body far from its middle"},
+		{resolv.NewVector(74, 159), resolv.NewVector(74, 90), tagMobBody, true, 74, 136, "a ray hits the body near its corner"},
+		{resolv.NewVector(0, 140), resolv.NewVector(150, 140), tagMobBody, false, 0, 0, "a ray passes below the body"},
+	} {
+		hit := Raycast(space, data.Start, data.End, data.Tag)
+		if (hit != nil) != data.Hit {
+			t.Errorf("Ray %v→%v hit was %t, want %t, because: %s", data.Start, data.End, hit != nil, data.Hit, data.Reason)
+			continue
+		}
+		if hit != nil && (math.Abs(hit.Point.X-data.WantX) > 1e-9 || math.Abs(hit.Point.Y-data.WantY) > 1e-9) {
+			t.Errorf("Ray %v→%v hit at %v, want (%g, %g), because: %s", data.Start, data.End, hit.Point, data.WantX, data.WantY, data.Reason)
+		}
+	}
+}
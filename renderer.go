@@ -5,8 +5,10 @@ package main
 
 import (
 	"image"
+	"log"
 	"path"
 	"sort"
+	"strings"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/solarlune/ldtkgo"
@@ -29,16 +31,46 @@ func (l *EmbedLoader) LoadTileset(tileSetPath string) *ebiten.Image {
 	return loadImage(path.Join(l.BasePath, tileSetPath))
 }
 
-// RenderedLayer represents an LDtk.Layer that was rendered out to an *ebiten.Image.
+// RenderedLayer represents an LDtk.Layer that is ready to be rendered out to
+// *ebiten.Images, one chunk of the map at a time.
 type RenderedLayer struct {
-	Image *ebiten.Image // The image that was rendered out
-	Layer *ldtkgo.Layer // The layer used to render the image
+	Layer    *ldtkgo.Layer   // The layer to render
+	Tileset  *ebiten.Image   // The layer's tileset image
+	Tiles    []*ldtkgo.Tile  // All the tiles on the layer that don't move
+	Animated []*AnimatedTile // Tiles on the layer that play an animation
+}
+
+// ForegroundLayer describes how an LDtk layer that is drawn above everything
+// walking around on the ground looks, how fast it scrolls is set in LDtk with
+// the layer's parallax factor, its "scale with parallax" option is ignored and
+// layers are always drawn at their real size
+type ForegroundLayer struct {
+	Shadow bool // Whether it casts a fake shadow on the ground
+	Fade   bool // Whether it fades out around the player and dog so they can see under it
+}
+
+// foregroundLayers maps identifiers (in lower case) of LDtk layers that are
+// drawn above the entities to how they look, layers whose identifiers start
+// with "Foreground" are drawn above too with the default look
+var foregroundLayers = map[string]ForegroundLayer{
+	"treetops": {Shadow: true, Fade: true},
+}
+
+// foregroundLayer returns how an LDtk layer looks if it's drawn above the
+// entities, the second return value is false for layers on the ground
+func foregroundLayer(identifier string) (ForegroundLayer, bool) {
+	if fl, ok := foregroundLayers[strings.ToLower(identifier)]; ok {
+		return fl, true
+	}
+	if strings.HasPrefix(strings.ToLower(identifier), "foreground") {
+		return ForegroundLayer{}, true
+	}
+	return ForegroundLayer{}, false
 }
 
 // TileRenderer is a struct that renders LDtk levels to *ebiten.Images.
 type TileRenderer struct {
 	Tilesets       map[string]*ebiten.Image
-	CurrentTileset string
 	RenderedLayers []*RenderedLayer
 	Loader         TilesetLoader // Loader for the renderer; defaults to a DiskLoader instance, though this can be switched out with something else as necessary.
 }
@@ -54,66 +86,22 @@ func NewTileRenderer(loader TilesetLoader) *TileRenderer {
 
 // Clear clears the renderer's Result.
 func (er *TileRenderer) Clear() {
-	for _, layer := range er.RenderedLayers {
-		layer.Image.Dispose()
-	}
 	er.RenderedLayers = []*RenderedLayer{}
 }
 
-// beginLayer gets called when necessary between rendering indidvidual Layers of a Level.
-func (er *TileRenderer) beginLayer(layer *ldtkgo.Layer, w, h int) {
-
-	_, exists := er.Tilesets[layer.Tileset.Path]
-
+// tileset loads the layer's tileset if it isn't loaded already.
+func (er *TileRenderer) tileset(layer *ldtkgo.Layer) *ebiten.Image {
+	tileset, exists := er.Tilesets[layer.Tileset.Path]
 	if !exists {
-		er.Tilesets[layer.Tileset.Path] = er.Loader.LoadTileset(layer.Tileset.Path)
+		tileset = er.Loader.LoadTileset(layer.Tileset.Path)
+		er.Tilesets[layer.Tileset.Path] = tileset
 	}
-
-	er.CurrentTileset = layer.Tileset.Path
-
-	renderedImage := ebiten.NewImage(w, h)
-
-	er.RenderedLayers = append(er.RenderedLayers, &RenderedLayer{Image: renderedImage, Layer: layer})
-
+	return tileset
 }
 
-// renderTile gets called by LDtkgo.Layer.RenderTiles(), and is currently provided the following arguments to handle rendering each tile in a Layer:
-// x, y = position of the drawn tile
-// srcX, srcY = position on the source tilesheet of the specified tile
-// srcW, srcH = width and height of the tile
-// flipBit = the flip bit of the tile; if the first bit is set, it should flip horizontally. If the second is set, it should flip vertically.
-func (er *TileRenderer) renderTile(x, y, srcX, srcY, srcW, srcH int, flipBit byte) {
-
-	// Subimage the Tile from the Tileset
-	tile := er.Tilesets[er.CurrentTileset].SubImage(image.Rect(srcX, srcY, srcX+srcW, srcY+srcH)).(*ebiten.Image)
-
-	opt := &ebiten.DrawImageOptions{}
-
-	// We have to offset the tile to be centered before flipping
-	opt.GeoM.Translate(float64(-srcW/2), float64(-srcH/2))
-
-	// Handle flipping; first bit in byte is horizontal flipping, second is vertical flipping.
-
-	if flipBit&1 > 0 {
-		opt.GeoM.Scale(-1, 1)
-	}
-	if flipBit&2 > 0 {
-		opt.GeoM.Scale(1, -1)
-	}
-
-	// Undo offsetting
-	opt.GeoM.Translate(float64(srcW/2), float64(srcH/2))
-
-	// Move tile to final position; note that slightly unlike LDtk, layer offsets in LDtk-Go are added directly into the final tiles' X and Y positions. This means that with this renderer,
-	// if a layer's offset pushes tiles outside of the layer's render Result image, they will be cut off. On LDtk, the tiles are still rendered, of course.
-	opt.GeoM.Translate(float64(x), float64(y))
-
-	// Finally, draw the tile to the Result image.
-	er.RenderedLayers[len(er.RenderedLayers)-1].Image.DrawImage(tile, opt)
-
-}
-
-// Render clears, and then renders out each visible Layer in an ldtgo.Level instance.
+// Render clears, and then prepares each visible Layer in an ldtgo.Level
+// instance for rendering. Nothing is drawn yet, the layers are drawn a chunk at
+// a time when they are needed using DrawChunk.
 func (er *TileRenderer) Render(level *ldtkgo.Level) {
 
 	er.Clear()
@@ -128,50 +116,86 @@ func (er *TileRenderer) Render(level *ldtkgo.Level) {
 			fallthrough
 		case ldtkgo.LayerTypeTile:
 			if tiles := layer.AllTiles(); len(tiles) > 0 {
+				rendered := &RenderedLayer{
+					Layer:   layer,
+					Tileset: er.tileset(layer),
+				}
+				// Animated tiles are kept apart to be drawn every frame
+				for _, tile := range tiles {
+					animation := tileAnimation(layer.Tileset, tile.ID)
+					if animation == nil {
+						rendered.Tiles = append(rendered.Tiles, tile)
+						continue
+					}
+					at := &AnimatedTile{
+						Tile:      tile,
+						Position:  image.Pt(tile.Position[0]+layer.OffsetX, tile.Position[1]+layer.OffsetY),
+						Animation: animation,
+					}
+					if animation.Stagger {
+						at.Phase = (tile.Position[0]/layer.GridSize*7 + tile.Position[1]/layer.GridSize*3) % len(animation.Frames)
+					}
+					rendered.Animated = append(rendered.Animated, at)
+				}
+				if len(rendered.Animated) > 0 {
+					log.Printf("Found %d animated tiles on layer %s", len(rendered.Animated), layer.Identifier)
+				}
+				er.RenderedLayers = append(er.RenderedLayers, rendered)
+			}
 
-				er.beginLayer(layer, level.Width, level.Height)
-
-				for _, tileData := range tiles {
-					// er.renderTile(tile.Position[0]+layer.OffsetX, tile.Position[1]+layer.OffsetY, tile.Src[0], tile.Src[1], layer.GridSize, layer.GridSize, tile.Flip)
-
-					// Subimage the Tile from the Tileset
-					tile := er.Tilesets[er.CurrentTileset].SubImage(image.Rect(tileData.Src[0], tileData.Src[1], tileData.Src[0]+layer.Tileset.GridSize, tileData.Src[1]+layer.Tileset.GridSize)).(*ebiten.Image)
+		}
 
-					opt := &ebiten.DrawImageOptions{}
+	}
 
-					// We have to offset the tile to be centered before flipping
-					opt.GeoM.Translate(float64(-layer.GridSize/2), float64(-layer.GridSize/2))
+	// Reverse sort the layers when drawing because in LDtk, the numbering order is from top-to-bottom, but the drawing order is from bottom-to-top.
+	sort.Slice(er.RenderedLayers, func(i, j int) bool {
+		return i > j
+	})
 
-					// Handle flipping; first bit in byte is horizontal flipping, second is vertical flipping.
+}
 
-					if tileData.FlipX() {
-						opt.GeoM.Scale(-1, 1)
-					}
-					if tileData.FlipY() {
-						opt.GeoM.Scale(1, -1)
-					}
+// DrawChunk draws the layer's tiles overlapping bounds (world coordinates) to
+// a chunk image whose top-left corner is at bounds.Min. The tiles are moved by
+// offset and coloured with cs, e.g. for drawing shadows.
+func (rl *RenderedLayer) DrawChunk(chunk *ebiten.Image, bounds image.Rectangle, offset image.Point, cs ebiten.ColorScale) {
+	layer := rl.Layer
+	for _, tileData := range rl.Tiles {
+		// Note that slightly unlike LDtk, layer offsets in LDtk-Go are added directly into the final tiles' X and Y positions.
+		position := image.Pt(tileData.Position[0]+layer.OffsetX, tileData.Position[1]+layer.OffsetY).Add(offset)
+		if !image.Rect(0, 0, layer.GridSize, layer.GridSize).Add(position).Overlaps(bounds) {
+			continue
+		}
 
-					// Undo offsetting
-					opt.GeoM.Translate(float64(layer.GridSize/2), float64(layer.GridSize/2))
+		// Subimage the Tile from the Tileset
+		tile := rl.Tileset.SubImage(image.Rect(tileData.Src[0], tileData.Src[1], tileData.Src[0]+layer.Tileset.GridSize, tileData.Src[1]+layer.Tileset.GridSize)).(*ebiten.Image)
 
-					// Move tile to final position; note that slightly unlike LDtk, layer offsets in LDtk-Go are added directly into the final tiles' X and Y positions. This means that with this renderer,
-					// if a layer's offset pushes tiles outside of the layer's render Result image, they will be cut off. On LDtk, the tiles are still rendered, of course.
-					opt.GeoM.Translate(float64(tileData.Position[0]+layer.OffsetX), float64(tileData.Position[1]+layer.OffsetY))
+		opt := tileOptions(tileData, layer.GridSize)
+		opt.ColorScale = cs
 
-					// Finally, draw the tile to the Result image.
-					er.RenderedLayers[len(er.RenderedLayers)-1].Image.DrawImage(tile, opt)
+		// Finally, draw the tile to its place in the chunk.
+		drawInChunk(chunk, tile, bounds, float64(position.X), float64(position.Y), opt)
+	}
+}
 
-				}
+// tileOptions creates the options for drawing a tile flipped the way it is on
+// the map, ready to be moved to its final position
+func tileOptions(tileData *ldtkgo.Tile, gridSize int) *ebiten.DrawImageOptions {
+	opt := &ebiten.DrawImageOptions{}
 
-			}
+	// We have to offset the tile to be centered before flipping
+	opt.GeoM.Translate(float64(-gridSize/2), float64(-gridSize/2))
 
-		}
+	// Handle flipping; first bit in byte is horizontal flipping, second is vertical flipping.
 
+	if tileData.FlipX() {
+		opt.GeoM.Scale(-1, 1)
+	}
+	if tileData.FlipY() {
+		opt.GeoM.Scale(1, -1)
 	}
 
-	// Reverse sort the layers when drawing because in LDtk, the numbering order is from top-to-bottom, but the drawing order is from bottom-to-top.
-	sort.Slice(er.RenderedLayers, func(i, j int) bool {
-		return i > j
-	})
+	// Undo offsetting
+	opt.GeoM.Translate(float64(gridSize/2), float64(gridSize/2))
 
+	return opt
 }
@@ -0,0 +1,106 @@
+// Use of this source code is subject to an MIT-style
+// licence which can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"image"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// resolution is the size of the game's screen in pixels before it's scaled up
+// to fit the window, either WIDTHxHEIGHT like 384x216 or an aspect ratio
+var resolution string = "4:3"
+
+// pixelPerfect only scales the screen up by whole numbers so every pixel is the
+// same size, leaving a black border if the window isn't an exact fit
+var pixelPerfect bool = false
+
+// Smallest screen that the HUD and menus still fit on
+const minScreenWidth, minScreenHeight = 240, 160
+
+// Biggest screen, twice the original 320x240 one, any bigger shows so much of
+// the map that zombies spawn in plain sight
+const maxScreenWidth, maxScreenHeight = 640, 480
+
+// aspectRatios are the resolutions picked when an aspect ratio is given instead
+// of a resolution, they're all as tall as the original 320x240 screen
+var aspectRatios = map[string]image.Point{
+	"4:3":   {320, 240},
+	"16:10": {384, 240},
+	"16:9":  {427, 240},
+	"21:9":  {560, 240},
+}
+
+// parseResolution works out the width and height of the screen from the
+// resolution setting
+func parseResolution(s string) (int, int, error) {
+	if size, ok := aspectRatios[s]; ok {
+		return size.X, size.Y, nil
+	}
+	var w, h int
+	if _, err := fmt.Sscanf(s, "%dx%d", &w, &h); err != nil || fmt.Sprintf("%dx%d", w, h) != s {
+		return 0, 0, fmt.Errorf("resolution %q is neither WIDTHxHEIGHT nor an aspect ratio", s)
+	}
+	if w < minScreenWidth || h < minScreenHeight {
+		return 0, 0, fmt.Errorf("resolution %q is smaller than %dx%d", s, minScreenWidth, minScreenHeight)
+	}
+	if w > maxScreenWidth || h > maxScreenHeight {
+		return 0, 0, fmt.Errorf("resolution %q is bigger than %dx%d", s, maxScreenWidth, maxScreenHeight)
+	}
+	return w, h, nil
+}
+
+// finalScreen remembers how the game's screen was last scaled up to the window,
+// fit is how Ebitengine would have done it and drawn is how it was really done
+var finalScreen struct {
+	fit, drawn ebiten.GeoM
+}
+
+// PixelPerfectGame is a Game that is only ever scaled up by whole numbers
+type PixelPerfectGame struct {
+	*Game
+}
+
+// DrawFinalScreen draws the game's screen to the middle of the window at the
+// biggest whole number scale that fits
+func (g *PixelPerfectGame) DrawFinalScreen(screen ebiten.FinalScreen, offscreen *ebiten.Image, geoM ebiten.GeoM) {
+	finalScreen.fit = geoM
+
+	// When the window is smaller than the screen it can only be scaled down
+	scale := geoM.Element(0, 0)
+	if scale >= 1 {
+		scale = math.Floor(scale)
+	}
+	sw, sh := float64(screen.Bounds().Dx()), float64(screen.Bounds().Dy())
+	w, h := float64(offscreen.Bounds().Dx()), float64(offscreen.Bounds().Dy())
+
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Scale(scale, scale)
+	op.GeoM.Translate(math.Floor((sw-w*scale)/2), math.Floor((sh-h*scale)/2))
+	if scale < 1 {
+		op.Filter = ebiten.FilterLinear
+	}
+	finalScreen.drawn = op.GeoM
+
+	screen.Clear()
+	screen.DrawImage(offscreen, op)
+}
+
+// cursorPosition is where the mouse is on the game's screen
+func cursorPosition() (float64, float64) {
+	cx, cy := ebiten.CursorPosition()
+	x, y := float64(cx), float64(cy)
+	if !pixelPerfect {
+		return x, y
+	}
+	// Ebitengine thinks the screen was scaled to fit the window, so go back to
+	// the window's coordinates and then to where the screen really was drawn
+	x, y = finalScreen.fit.Apply(x, y)
+	drawn := finalScreen.drawn
+	drawn.Invert()
+	return drawn.Apply(x, y)
+}
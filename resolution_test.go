@@ -0,0 +1,40 @@
+// Use of this source code is subject to an MIT-style
+// licence which can be found in the LICENSE file.
+
+package main
+
+import "testing"
+
+func TestParseResolution(t *testing.T) {
+	for _, data := range []struct {
+		Resolution string
+		W, H       int
+		OK         bool
+	}{
+		{"4:3", 320, 240, true},
+		{"16:10", 384, 240, true},
+		{"16:9", 427, 240, true},
+		{"21:9", 560, 240, true},
+		{"384x216", 384, 216, true},
+		{"240x160", 240, 160, true},
+		{"640x480", 640, 480, true},
+		{"239x160", 0, 0, false},
+		{"240x159", 0, 0, false},
+		{"641x480", 0, 0, false},
+		{"640x481", 0, 0, false},
+		{"3840x2160", 0, 0, false},
+		{"-320x240", 0, 0, false},
+		{"320x240x2", 0, 0, false},
+		{"320 x 240", 0, 0, false},
+		{"3:2", 0, 0, false},
+		{"", 0, 0, false},
+	} {
+		w, h, err := parseResolution(data.Resolution)
+		if (err == nil) != data.OK {
+			t.Errorf("parseResolution(%q) error = %v, want ok %v", data.Resolution, err, data.OK)
+		}
+		if w != data.W || h != data.H {
+			t.Errorf("parseResolution(%q) = %dx%d, want %dx%d", data.Resolution, w, h, data.W, data.H)
+		}
+	}
+}
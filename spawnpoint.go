@@ -8,6 +8,11 @@ import (
 	"math/rand"
 )
 
+// spawnDelay is the shortest time (ticks) a spawn point waits before spawning
+// another zombie, it waits up to twice as long, the director changes it
+// depending on how the player is doing
+var spawnDelay int = 180
+
 // SpawnPoints is an array of SpawnPoint
 type SpawnPoints []*SpawnPoint
 
@@ -67,33 +72,27 @@ func (s *SpawnPoint) SpawnZombie(g *GameScreen) {
 		}
 	}
 
+	// The director decides on the mix of zombies
+	zombieType := g.Director.ZombieType(s.ZombieType)
+
 	var sprites *SpriteSheet
-	switch s.ZombieType {
+	switch zombieType {
 	case zombieNormal:
-		fallthrough
+		sprites = g.ZombieSprites[rand.Intn(zombieVariants)]
 	case zombieCrawler:
-		zs := rand.Intn(zombieVariants + 1)
-		if zs == zombieVariants {
-			// Crawler
-			sprites = g.Sprites[spriteZombieCrawler]
-			s.ZombieType = zombieCrawler
-		} else {
-			// Normal
-			sprites = g.ZombieSprites[zs]
-			s.ZombieType = zombieNormal
-		}
+		sprites = g.Sprites[spriteZombieCrawler]
 	case zombieSprinter:
 		sprites = g.Sprites[spriteZombieSprinter]
 	case zombieBig:
 		sprites = g.Sprites[spriteZombieBig]
 	}
 
-	z := NewZombie(s, nc, s.ZombieType, sprites)
+	z := NewZombie(s, nc, zombieType, sprites)
 
 	z.Target = &g.Player.Object.Position
-	g.Space.Add(z.Object)
+	g.Space.Add(z.Object, z.Body)
 
-	if s.ZombieType == zombieBig {
+	if zombieType == zombieBig {
 		boss := &Boss{Zombie: z}
 		g.Zombies = append(g.Zombies, boss)
 		s.Zombies = append(s.Zombies, boss)
@@ -101,23 +100,28 @@ func (s *SpawnPoint) SpawnZombie(g *GameScreen) {
 		g.Zombies = append(g.Zombies, z)
 		s.Zombies = append(s.Zombies, z)
 	}
-	s.NextSpawn = 180 + rand.Intn(180)
+	s.NextSpawn = g.Director.SpawnDelay()
 }
 
 // Update updates the state of the spawn point
 func (s *SpawnPoint) Update(g *GameScreen) {
 
+	// How far from the player the edge of the screen can be, the camera can
+	// zoom out and move off the player to keep the dog in frame too
+	player := g.Player.Object.Position
+	visible := float64(g.Width)/g.Camera.Scale/2 + CalcDistance(g.Camera.X, g.Camera.Y, player.X, player.Y)
+
 	// spawnMaxDistance is the distance where the point is activated, if the player is close enough
-	var spawnMaxDistance = float64(g.Width)/2 + 150
+	var spawnMaxDistance = visible + 150
 
 	// spawnMinDistance is the distance where the point is deactivated, if the player is too close
-	var spawnMinDistance = float64(g.Width)/2 + 50
+	var spawnMinDistance = visible + 50
 
 	if s.InitialSpawned && !s.Continuous {
 		return
 	}
 
-	playerDistance := CalcDistance(s.Position.X, s.Position.Y, g.Player.Object.Position.X, g.Player.Object.Position.Y)
+	playerDistance := CalcDistance(s.Position.X, s.Position.Y, player.X, player.Y)
 
 	// Spawn point is activated if the player is close enougn, but not too close
 	if playerDistance < spawnMaxDistance && playerDistance > spawnMinDistance {
@@ -5,30 +5,34 @@ package main
 
 import (
 	"image/color"
+	"math"
 
 	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
 	"github.com/tanema/gween"
 	"github.com/tanema/gween/ease"
 	"github.com/tinne26/etxt"
 )
 
-const startText = "Press space to start"
-
 // StartScreen is the first screen you see when you start the game, it shows you
 // a menu that lets you start a game or change game options etc.
 type StartScreen struct {
-	background   *ebiten.Image
-	textRenderer *StartTextRenderer
-	textFader    *gween.Sequence
+	background         *ebiten.Image
+	textRenderer       *StartTextRenderer
+	difficultyRenderer *StartTextRenderer
+	textFader          *gween.Sequence
 }
 
 func NewStartScreen(game *Game) *StartScreen {
 	fadeSeq := gween.NewSequence(gween.New(50, 200, 60, ease.OutQuad))
 	fadeSeq.SetYoyo(true)
+	difficultyRenderer := NewStartTextRenderer()
+	difficultyRenderer.alpha = 0xff
 	return &StartScreen{
-		background:   loadImage("assets/splash-screen.png"),
-		textRenderer: NewStartTextRenderer(),
-		textFader:    fadeSeq,
+		background:         loadImage("assets/splash-screen.png"),
+		textRenderer:       NewStartTextRenderer(),
+		difficultyRenderer: difficultyRenderer,
+		textFader:          fadeSeq,
 	}
 }
 
@@ -39,7 +43,18 @@ func (s *StartScreen) Update() (GameState, error) {
 		return gameIntro, nil
 	}
 
+	// Left and right arrows pick the difficulty
+	if inpututil.IsKeyJustPressed(ebiten.KeyArrowLeft) {
+		cycleDifficulty(-1)
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyArrowRight) {
+		cycleDifficulty(1)
+	}
+
 	alpha, _, _ := s.textFader.Update(1)
+	if reducedMotion {
+		alpha = 200 // Don't flash the text
+	}
 	s.textRenderer.alpha = uint8(alpha)
 
 	return gameStart, nil
@@ -47,8 +62,16 @@ func (s *StartScreen) Update() (GameState, error) {
 
 // Draw renders the start screen to the screen
 func (s *StartScreen) Draw(screen *ebiten.Image) {
-	screen.DrawImage(s.background, &ebiten.DrawImageOptions{})
-	s.textRenderer.Draw(screen, startText)
+	// Cover the whole screen with the background, even if it gets cut off
+	sw, sh := screen.Bounds().Dx(), screen.Bounds().Dy()
+	bw, bh := s.background.Bounds().Dx(), s.background.Bounds().Dy()
+	scale := math.Max(float64(sw)/float64(bw), float64(sh)/float64(bh))
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Scale(scale, scale)
+	op.GeoM.Translate((float64(sw)-float64(bw)*scale)/2, (float64(sh)-float64(bh)*scale)/2)
+	screen.DrawImage(s.background, op)
+	s.difficultyRenderer.Draw(screen, tr("start.difficulty", tr("difficulty."+difficulty)), screen.Bounds().Dy()/8*7-12)
+	s.textRenderer.Draw(screen, tr("start.prompt"), screen.Bounds().Dy()/8*7)
 }
 
 // StartTextRenderer wraps etxt.Renderer to draw full-screen text
@@ -67,10 +90,11 @@ func NewStartTextRenderer() *StartTextRenderer {
 	return &StartTextRenderer{r, 0}
 }
 
-func (r StartTextRenderer) Draw(screen *ebiten.Image, text string) {
+// Draw draws the text in the middle of the screen at height y with a shadow
+func (r StartTextRenderer) Draw(screen *ebiten.Image, text string, y int) {
 	r.SetTarget(screen)
 	r.SetColor(color.RGBA{0x0, 0x0, 0x0, r.alpha})
-	r.Renderer.Draw(text, screen.Bounds().Dx()/2+1, screen.Bounds().Dy()/8*7+1)
+	r.Renderer.Draw(text, screen.Bounds().Dx()/2+1, y+1)
 	r.SetColor(color.RGBA{0xff, 0xff, 0xff, r.alpha})
-	r.Renderer.Draw(text, screen.Bounds().Dx()/2, screen.Bounds().Dy()/8*7)
+	r.Renderer.Draw(text, screen.Bounds().Dx()/2, y)
 }
@@ -14,4 +14,6 @@ type Stat struct {
 	CounterZombiesKilled int
 	CounterPlayerDied    int
 	CounterDogDied       int
+	Difficulty           string // Name of the difficulty preset played on
+	Assisted             bool   // Whether any assists were turned on
 }
@@ -0,0 +1,121 @@
+// Use of this source code is subject to an MIT-style
+// licence which can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"image/color"
+	"log"
+	"path"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/audio"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/tinne26/etxt"
+)
+
+// subtitlesEnabled is whether subtitles are shown for voice lines
+var subtitlesEnabled bool = true
+
+// subtitlesFile lists the subtitles for every voice line, keyed by the name of
+// the voice file without its extension, times are in seconds
+const subtitlesFile = "assets/voice/subtitles.json"
+
+// How far (pixels) from the bottom of the screen subtitles are shown
+const subtitleMargin = 24
+
+// Colours of the subtitles
+var (
+	colorSubtitle         = color.RGBA{0xff, 0xff, 0xff, 0xff}
+	colorSubtitleBackdrop = color.RGBA{0x00, 0x00, 0x00, 0xa0}
+)
+
+// Cue is one line of subtitles shown while a voice line is playing
+type Cue struct {
+	Start float64 `json:"start"` // When (seconds) to start showing the text
+	End   float64 `json:"end"`   // When (seconds) to stop showing the text
+	Text  string  `json:"text"`
+}
+
+// SubtitleTrack is all the subtitles for one voice line in order
+type SubtitleTrack []Cue
+
+// subtitles is every subtitle track loaded from subtitlesFile, it's loaded the
+// first time a track is needed
+var subtitles map[string]SubtitleTrack
+
+// subtitleTrack returns the subtitles for a voice file, or nil if it hasn't got
+// any
+func subtitleTrack(filename string) SubtitleTrack {
+	if subtitles == nil {
+		subtitles = map[string]SubtitleTrack{}
+		data, err := assets.ReadFile(subtitlesFile)
+		if err != nil {
+			log.Printf("error reading file %s: %v\n", subtitlesFile, err)
+			return nil
+		}
+		if err := json.Unmarshal(data, &subtitles); err != nil {
+			log.Printf("error parsing file %s as subtitles: %v\n", subtitlesFile, err)
+			return nil
+		}
+	}
+	return subtitles[strings.TrimSuffix(path.Base(filename), ".ogg")]
+}
+
+// At returns the text to show at the time (seconds) into the voice line, or
+// an empty string if nothing is being said then
+func (t SubtitleTrack) At(seconds float64) string {
+	for _, c := range t {
+		if seconds >= c.Start && seconds < c.End {
+			return c.Text
+		}
+	}
+	return ""
+}
+
+// SubtitleRenderer wraps etxt.Renderer to draw subtitles at the bottom of the
+// screen
+type SubtitleRenderer struct {
+	*etxt.Renderer
+	Margin int // How far (pixels) from the bottom of the screen they're shown
+}
+
+// NewSubtitleRenderer creates a text renderer for subtitles
+func NewSubtitleRenderer() *SubtitleRenderer {
+	r := etxt.NewStdRenderer()
+	r.SetFont(loadFont("assets/fonts/PixelOperator8.ttf"))
+	r.SetAlign(etxt.YCenter, etxt.XCenter)
+	r.SetSizePx(8)
+	return &SubtitleRenderer{r, subtitleMargin}
+}
+
+// Draw draws the subtitle for what the player is saying right now, if anything
+func (r *SubtitleRenderer) Draw(screen *ebiten.Image, player *audio.Player, track SubtitleTrack) {
+	if !subtitlesEnabled || player == nil || !player.IsPlaying() {
+		return
+	}
+	text := track.At(player.Position().Seconds())
+	if text == "" {
+		return
+	}
+
+	x := screen.Bounds().Dx() / 2
+	y := screen.Bounds().Dy() - r.Margin
+	r.SetTarget(screen)
+	size := r.SelectionRect(text)
+	w, h := float32(size.Width.Ceil()+8), float32(size.Height.Ceil()+4)
+	vector.DrawFilledRect(screen, float32(x)-w/2, float32(y)-h/2, w, h, colorSubtitleBackdrop, false)
+	r.SetColor(colorSubtitle)
+	r.Renderer.Draw(text, x, y)
+}
+
+// DrawVoices draws the subtitles for whichever of the voices is playing
+func (r *SubtitleRenderer) DrawVoices(screen *ebiten.Image, voices Sounds) {
+	for _, v := range voices {
+		if v.LastPlayed != nil && v.LastIndex < len(v.Subtitles) {
+			r.Draw(screen, v.LastPlayed, v.Subtitles[v.LastIndex])
+		}
+	}
+}
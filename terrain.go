@@ -0,0 +1,199 @@
+// Use of this source code is subject to an MIT-style
+// licence which can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"strconv"
+	"strings"
+
+	"github.com/solarlune/resolv"
+)
+
+// EntityKind is a kind of thing that moves around on the terrain
+type EntityKind uint8
+
+const (
+	entityPlayer EntityKind = iota
+	entityDog
+	entityZombie
+)
+
+// How often (ticks) walking over terrain kicks up particles
+const terrainKickInterval = 8
+
+// Terrain is a kind of terrain that can be painted onto any IntGrid layer in
+// LDtk, the IntGrid value's identifier in LDtk says how the terrain behaves
+type Terrain struct {
+	Name     string                 // The IntGrid value's identifier
+	Tags     []string               // Tags of the collision objects created for the terrain
+	Obstacle bool                   // Whether path planning should go around it
+	Speed    map[EntityKind]float64 // Speed multipliers for each kind of entity, 1 if not set
+	Footstep *float64               // How loud footsteps are on it, nil if not set
+	Sound    string                 // Name of its own footstep sound, plain ground's if not set
+	Kick     color.RGBA             // Colour of particles kicked up walking over it, none if transparent
+	Map      color.RGBA             // Colour on the minimap, not shown if transparent
+}
+
+// How see-through particles kicked up from terrain are
+const terrainKickAlpha = 0xc0
+
+// ParseTerrain works out how terrain behaves from the identifier and colour
+// of its IntGrid value in LDtk, so that new terrain can be added to the map
+// without changing the game. The identifier is made of words separated by
+// underscores, these words change how the terrain behaves:
+//
+//	solid      blocks movement, path planning goes around it
+//	cover      blocks bullets and sight
+//	slowNN     everything moves at NN% speed over it
+//	playerNN   the player moves at NN% speed, also dogNN and zombieNN
+//	stepNN     footsteps are NN% as loud
+//	soundNAME  footsteps sound like assets/sfx/Footstep-NAME.ogg
+//	kick       walking over it kicks up particles in the value's colour
+//	hidden     it isn't shown on the minimap, otherwise it's the value's colour
+//
+// Any other words are just part of the name, e.g. Water_solid is water you
+// can't walk through but can shoot and see over and Wall_solid_cover is a wall.
+func ParseTerrain(identifier, colour string) *Terrain {
+	c := parseHexColor(colour)
+	terrain := &Terrain{Name: identifier, Tags: []string{tagTerrain}, Map: c}
+	slow, speeds := 1.0, map[EntityKind]float64{}
+	for _, word := range strings.Split(strings.ToLower(identifier), "_") {
+		switch word {
+		case "solid":
+			terrain.Obstacle = true
+			terrain.Tags = append(terrain.Tags, tagWall)
+		case "cover":
+			terrain.Tags = append(terrain.Tags, tagCover)
+		case "kick":
+			terrain.Kick = c
+			terrain.Kick.A = terrainKickAlpha
+		case "hidden":
+			terrain.Map = color.RGBA{}
+		}
+		if percent, ok := wordPercent(word, "slow"); ok {
+			slow = percent
+		}
+		if percent, ok := wordPercent(word, "step"); ok {
+			terrain.Footstep = &percent
+		}
+		if strings.HasPrefix(word, "sound") && len(word) > len("sound") {
+			terrain.Sound = strings.TrimPrefix(word, "sound")
+		}
+		for prefix, kind := range terrainSpeedWords {
+			if percent, ok := wordPercent(word, prefix); ok {
+				speeds[kind] = percent
+			}
+		}
+	}
+
+	// The speed for one kind of entity wins over the speed for everything
+	if slow != 1 || len(speeds) > 0 {
+		terrain.Speed = map[EntityKind]float64{}
+		for _, kind := range terrainSpeedWords {
+			terrain.Speed[kind] = slow
+		}
+		for kind, speed := range speeds {
+			terrain.Speed[kind] = speed
+		}
+	}
+	return terrain
+}
+
+// terrainSpeedWords are the words in an IntGrid value's identifier that change
+// how fast one kind of entity moves over it
+var terrainSpeedWords = map[string]EntityKind{
+	"player": entityPlayer,
+	"dog":    entityDog,
+	"zombie": entityZombie,
+}
+
+// wordPercent reads a word made of a prefix and a percentage like slow50 as a
+// fraction like 0.5
+func wordPercent(word, prefix string) (float64, bool) {
+	if !strings.HasPrefix(word, prefix) {
+		return 0, false
+	}
+	percent, err := strconv.Atoi(strings.TrimPrefix(word, prefix))
+	if err != nil || percent < 0 {
+		return 0, false
+	}
+	return float64(percent) / 100, true
+}
+
+// parseHexColor reads a colour written like #RRGGBB, as LDtk does, or returns
+// transparent if it can't
+func parseHexColor(s string) color.RGBA {
+	var c color.RGBA
+	if _, err := fmt.Sscanf(s, "#%02x%02x%02x", &c.R, &c.G, &c.B); err != nil {
+		return color.RGBA{}
+	}
+	c.A = 0xff
+	return c
+}
+
+// IntGridValues maps IntGrid layer identifiers to the terrain each of the
+// layer's values represents
+type IntGridValues map[string]map[int]*Terrain
+
+// TerrainAt looks up what kind of terrain a value on an IntGrid layer is
+func (v IntGridValues) TerrainAt(layer string, value int) (*Terrain, bool) {
+	terrain, ok := v[layer][value]
+	return terrain, ok
+}
+
+// TerrainUnder returns the terrain under the object that slows it down the
+// most, or nil if it's on plain ground
+func TerrainUnder(o *resolv.Object, kind EntityKind) *Terrain {
+	var slowest *Terrain
+	if collision := o.Check(0, 0, tagTerrain); collision != nil {
+		for _, t := range collision.Objects {
+			if o.Shape.Intersection(0, 0, t.Shape) == nil {
+				continue
+			}
+			terrain := t.Data.(*Terrain)
+			if slowest == nil || terrain.SpeedFor(kind) < slowest.SpeedFor(kind) {
+				slowest = terrain
+			}
+		}
+	}
+	return slowest
+}
+
+// SpeedFor returns how much the terrain slows down the kind of entity
+func (t *Terrain) SpeedFor(kind EntityKind) float64 {
+	if t == nil {
+		return 1
+	}
+	if speed, ok := t.Speed[kind]; ok {
+		return speed
+	}
+	return 1
+}
+
+// FootstepVolume returns how loud footsteps are on the terrain
+func (t *Terrain) FootstepVolume() float64 {
+	if t == nil || t.Footstep == nil {
+		return 1
+	}
+	return *t.Footstep
+}
+
+// FootstepSound returns the name of the terrain's footstep sound, or an empty
+// string for the sound on plain ground
+func (t *Terrain) FootstepSound() string {
+	if t == nil {
+		return ""
+	}
+	return t.Sound
+}
+
+// KickUp kicks up particles every now and then while walking over the terrain
+func (t *Terrain) KickUp(g *GameScreen, position Coord, angle float64) {
+	if t == nil || t.Kick.A == 0 || g.Tick%terrainKickInterval != 0 {
+		return
+	}
+	g.Particles.EmitKick(position, angle, t.Kick)
+}
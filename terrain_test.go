@@ -0,0 +1,90 @@
+// Use of this source code is subject to an MIT-style
+// licence which can be found in the LICENSE file.
+
+package main
+
+import (
+	"image/color"
+	"reflect"
+	"testing"
+)
+
+func TestParseTerrain(t *testing.T) {
+	sand := color.RGBA{0xd8, 0xc0, 0x80, 0xff}
+	half, silent := 0.5, 0.0
+	for _, data := range []struct {
+		Identifier, Colour string
+		Want               Terrain
+		Reason             string
+	}{
+		{
+			"Wall_solid_cover", "#302820",
+			Terrain{Tags: []string{tagTerrain, tagWall, tagCover}, Obstacle: true, Map: color.RGBA{0x30, 0x28, 0x20, 0xff}},
+			"walls block movement, bullets and sight",
+		},
+		{
+			"Water_solid", "#3060A0",
+			Terrain{Tags: []string{tagTerrain, tagWall}, Obstacle: true, Map: color.RGBA{0x30, 0x60, 0xa0, 0xff}},
+			"water blocks movement but can be shot over",
+		},
+		{
+			"Sand_trap_slow50_step50_kick_soundsand", "#D8C080",
+			Terrain{
+				Tags:     []string{tagTerrain},
+				Speed:    map[EntityKind]float64{entityPlayer: 0.5, entityDog: 0.5, entityZombie: 0.5},
+				Footstep: &half,
+				Sound:    "sand",
+				Kick:     color.RGBA{0xd8, 0xc0, 0x80, terrainKickAlpha},
+				Map:      sand,
+			},
+			"sand traps slow everyone down, muffle footsteps and kick up sand",
+		},
+		{
+			"Bush_dog80_slow70_zombie60", "#D8C080",
+			Terrain{
+				Tags:  []string{tagTerrain},
+				Speed: map[EntityKind]float64{entityPlayer: 0.7, entityDog: 0.8, entityZombie: 0.6},
+				Map:   sand,
+			},
+			"speeds for one kind of entity win over the speed for everything in any order",
+		},
+		{
+			"Moss_step0", "#00FF00",
+			Terrain{Tags: []string{tagTerrain}, Footstep: &silent, Map: color.RGBA{0x00, 0xff, 0x00, 0xff}},
+			"footsteps can be made silent",
+		},
+		{
+			"Grass_hidden", "#00FF00",
+			Terrain{Tags: []string{tagTerrain}},
+			"hidden terrain isn't on the minimap",
+		},
+		{
+			"Doghouse_slowly_sound", "not a colour",
+			Terrain{Tags: []string{tagTerrain}},
+			"words that only start like keywords are just part of the name",
+		},
+	} {
+		data.Want.Name = data.Identifier
+		got := ParseTerrain(data.Identifier, data.Colour)
+		if !reflect.DeepEqual(*got, data.Want) {
+			t.Errorf("Terrain %s was %+v, want %+v, because: %s", data.Identifier, *got, data.Want, data.Reason)
+		}
+	}
+}
+
+func TestTerrainFootstepVolume(t *testing.T) {
+	for _, data := range []struct {
+		Terrain *Terrain
+		Want    float64
+		Reason  string
+	}{
+		{nil, 1, "plain ground has full volume footsteps"},
+		{ParseTerrain("Sand", "#D8C080"), 1, "terrain without a step word has full volume footsteps"},
+		{ParseTerrain("Sand_step50", "#D8C080"), 0.5, "step50 halves the volume"},
+		{ParseTerrain("Moss_step0", "#00FF00"), 0, "step0 is silent"},
+	} {
+		if got := data.Terrain.FootstepVolume(); got != data.Want {
+			t.Errorf("Footstep volume was %g, want %g, because: %s", got, data.Want, data.Reason)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+// Use of this source code is subject to an MIT-style
+// licence which can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"image"
+	"log"
+
+	"github.com/solarlune/ldtkgo"
+)
+
+// TileAnimation is a sequence of tiles from a tileset that are shown one after
+// the other in place of an animated tile on the map.
+//
+// An animation can be set on a tile in LDtk in the tile's custom data, as JSON
+// like {"frames": [12, 13, 14, 15], "frameTime": 8, "stagger": true}
+type TileAnimation struct {
+	Frames    []int `json:"frames"`    // Tile IDs of the frames in order
+	FrameTime int   `json:"frameTime"` // How long (ticks) each frame is shown
+	Stagger   bool  `json:"stagger"`   // Whether tiles start at different frames so they don't all move together
+}
+
+// EnumAnimation is an animation for tiles tagged with an enum value in LDtk,
+// the frames are the tagged tile followed by the next tiles in the tileset, so
+// only the first frame of the animation should be tagged
+type EnumAnimation struct {
+	Length    int  // How many frames the animation has
+	FrameTime int  // How long (ticks) each frame is shown
+	Stagger   bool // Whether tiles start at different frames
+}
+
+// tileAnimations maps enum values tiles can be tagged with in LDtk to the
+// animation they should play
+var tileAnimations = map[string]EnumAnimation{
+	"Fire":  {Length: 4, FrameTime: 6, Stagger: true},
+	"Water": {Length: 4, FrameTime: 15},
+	"Grass": {Length: 4, FrameTime: 10, Stagger: true},
+}
+
+// AnimatedTile is a tile on the map that plays an animation, it isn't baked
+// into the map chunks but drawn every frame instead
+type AnimatedTile struct {
+	Tile      *ldtkgo.Tile
+	Position  image.Point // Top-left corner of the tile on the map
+	Animation *TileAnimation
+	Phase     int // How many frames into the animation the tile starts
+}
+
+// tileAnimation looks up the animation for a tile from its custom data or its
+// enum tags, it returns nil if the tile isn't animated
+func tileAnimation(tileset *ldtkgo.Tileset, id int) *TileAnimation {
+	if data := tileset.CustomDataForTile(id); data != "" {
+		animation := &TileAnimation{}
+		if err := json.Unmarshal([]byte(data), animation); err != nil {
+			log.Printf("Cannot read custom data of tile %d in %s: %v", id, tileset.Identifier, err)
+		} else if len(animation.Frames) > 0 {
+			if animation.FrameTime < 1 {
+				animation.FrameTime = 1
+			}
+			return animation
+		}
+	}
+
+	for _, enum := range tileset.EnumsForTile(id) {
+		if a, ok := tileAnimations[enum]; ok {
+			animation := &TileAnimation{FrameTime: a.FrameTime, Stagger: a.Stagger}
+			for i := 0; i < a.Length; i++ {
+				animation.Frames = append(animation.Frames, id+i)
+			}
+			return animation
+		}
+	}
+
+	return nil
+}
+
+// Frame returns the tile ID of the frame the tile should show at the tick
+func (at *AnimatedTile) Frame(tick int) int {
+	a := at.Animation
+	return a.Frames[(tick/a.FrameTime+at.Phase)%len(a.Frames)]
+}
+
+// tileSrc returns where the tile with the ID is on the tileset image
+func tileSrc(tileset *ldtkgo.Tileset, id int) image.Rectangle {
+	step := tileset.GridSize + tileset.Spacing
+	columns := (tileset.Width - tileset.Padding*2 + tileset.Spacing) / step
+	if columns < 1 {
+		columns = 1
+	}
+	x := tileset.Padding + id%columns*step
+	y := tileset.Padding + id/columns*step
+	return image.Rect(x, y, x+tileset.GridSize, y+tileset.GridSize)
+}
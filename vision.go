@@ -0,0 +1,174 @@
+// Use of this source code is subject to an MIT-style
+// licence which can be found in the LICENSE file.
+
+package main
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"sort"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/solarlune/resolv"
+)
+
+// fogOfWarEnabled is whether the parts of the screen the player can't see are
+// covered up and the zombies there hidden
+var fogOfWarEnabled bool = true
+
+// fogColor is what's drawn over the parts of the screen the player can't see
+var fogColor = color.RGBA{0x08, 0x08, 0x10, 0xb0}
+
+// How far (radians) either side of each wall corner to look, so that rays
+// also go past corners and hit whatever is behind them
+const visionCornerOffset = 0.0001
+
+// How far (pixels) outside the screen walls are still taken into account
+const visionMargin = 32
+
+// Vision works out what the player can see from where they are standing, walls
+// that stop bullets block the view but you can see over water and fences
+type Vision struct {
+	Origin  Coord   // Where the player is looking from
+	Polygon []Coord // Outline of the visible area, ordered by angle
+	edges   [][2]resolv.Vector
+	fog     *ebiten.Image
+	white   *ebiten.Image
+}
+
+// NewVision creates a new vision tracker that can't see anything yet
+func NewVision() *Vision {
+	return &Vision{}
+}
+
+// Update works out what the player can see from where they are now
+func (v *Vision) Update(g *GameScreen) {
+	v.Origin = *g.Player.Position()
+	origin := resolv.NewVector(v.Origin.X, v.Origin.Y)
+	view := cameraView(g.Camera).Inset(-visionMargin)
+
+	// The edges of the view stop the rays if no wall does
+	v.edges = v.edges[:0]
+	corners := []resolv.Vector{
+		resolv.NewVector(float64(view.Min.X), float64(view.Min.Y)),
+		resolv.NewVector(float64(view.Max.X), float64(view.Min.Y)),
+		resolv.NewVector(float64(view.Max.X), float64(view.Max.Y)),
+		resolv.NewVector(float64(view.Min.X), float64(view.Max.Y)),
+	}
+	v.addEdges(corners)
+
+	// Collect the edges of all the walls in view, every corner is somewhere
+	// the outline of what's visible can change direction
+	checked := map[*resolv.Object]bool{}
+	sx, sy := g.Space.WorldToSpace(float64(view.Min.X), float64(view.Min.Y))
+	ex, ey := g.Space.WorldToSpace(float64(view.Max.X), float64(view.Max.Y))
+	for _, o := range g.Space.CheckCells(sx, sy, ex-sx+1, ey-sy+1, tagCover) {
+		if checked[o] {
+			continue
+		}
+		checked[o] = true
+		if polygon, ok := o.Shape.(*resolv.ConvexPolygon); ok {
+			verts := polygon.Transformed()
+			v.addEdges(verts)
+			corners = append(corners, verts...)
+		}
+	}
+
+	var angles []float64
+	for _, c := range corners {
+		a := math.Atan2(c.Y-origin.Y, c.X-origin.X)
+		angles = append(angles, a-visionCornerOffset, a, a+visionCornerOffset)
+	}
+	sort.Float64s(angles)
+
+	// Long enough to reach the edge of the view from anywhere in it
+	reach := math.Hypot(float64(view.Dx()), float64(view.Dy()))
+	v.Polygon = v.Polygon[:0]
+	for _, a := range angles {
+		end := origin.Add(resolv.NewVector(math.Cos(a), math.Sin(a)).Scale(reach))
+		t := v.nearest(origin, end)
+		v.Polygon = append(v.Polygon, Coord{
+			X: origin.X + (end.X-origin.X)*t,
+			Y: origin.Y + (end.Y-origin.Y)*t,
+		})
+	}
+}
+
+// addEdges adds the edges of a closed polygon to the edges that block the view
+func (v *Vision) addEdges(verts []resolv.Vector) {
+	for i := range verts {
+		v.edges = append(v.edges, [2]resolv.Vector{verts[i], verts[(i+1)%len(verts)]})
+	}
+}
+
+// nearest returns how far (from 0 to 1) along the line from start to end the
+// first edge blocking the view is, 1 if nothing is in the way
+func (v *Vision) nearest(start, end resolv.Vector) float64 {
+	nearest := 1.0
+	for _, e := range v.edges {
+		if t, ok := segmentIntersection(start, end, e[0], e[1]); ok && t < nearest {
+			nearest = t
+		}
+	}
+	return nearest
+}
+
+// CanSee returns whether the player can see the position from where they are
+func (v *Vision) CanSee(position Coord) bool {
+	if !fogOfWarEnabled {
+		return true
+	}
+	start := resolv.NewVector(v.Origin.X, v.Origin.Y)
+	end := resolv.NewVector(position.X, position.Y)
+	return v.nearest(start, end) >= 1
+}
+
+// InLineOfSight returns whether no wall is between the player and the
+// position, unlike CanSee it works for positions off the screen too, which are
+// always out of view
+func (v *Vision) InLineOfSight(g *GameScreen, position Coord) bool {
+	if !fogOfWarEnabled {
+		return true
+	}
+	start := resolv.NewVector(v.Origin.X, v.Origin.Y)
+	end := resolv.NewVector(position.X, position.Y)
+	return Raycast(g.Space, start, end, tagCover) == nil
+}
+
+// Draw covers up everything the player can't see
+func (v *Vision) Draw(g *GameScreen) {
+	if !fogOfWarEnabled || len(v.Polygon) == 0 {
+		return
+	}
+
+	w, h := g.Camera.Surface.Size()
+	if v.fog == nil || v.fog.Bounds().Size() != image.Pt(w, h) {
+		if v.fog != nil {
+			v.fog.Dispose()
+		}
+		v.fog = ebiten.NewImage(w, h)
+	}
+	v.fog.Fill(fogColor)
+	if v.white == nil {
+		v.white = ebiten.NewImage(1, 1)
+		v.white.Fill(color.White)
+	}
+
+	// Cut the visible area out of the fog as a fan of triangles around the
+	// player, it's always one shape since it's all visible from the middle
+	ox, oy := surfaceCoords(g.Camera, v.Origin.X, v.Origin.Y)
+	vertices := []ebiten.Vertex{{DstX: ox, DstY: oy, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1}}
+	var indices []uint16
+	for i, p := range v.Polygon {
+		x, y := surfaceCoords(g.Camera, p.X, p.Y)
+		vertices = append(vertices, ebiten.Vertex{DstX: x, DstY: y, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1})
+		next := (i+1)%len(v.Polygon) + 1
+		indices = append(indices, 0, uint16(i+1), uint16(next))
+	}
+	v.fog.DrawTriangles(vertices, indices, v.white, &ebiten.DrawTrianglesOptions{
+		Blend: ebiten.BlendClear,
+	})
+
+	g.Camera.Surface.DrawImage(v.fog, nil)
+}
@@ -5,7 +5,6 @@ package main
 
 import (
 	"image/color"
-	"fmt"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/tinne26/etxt"
@@ -61,14 +60,18 @@ func (s *WinScreen) Update() (GameState, error) {
 
 func (s *WinScreen) Draw(screen *ebiten.Image) {
 	s.textRenderer.Renderer.SetTarget(screen)
-	s.textRenderer.Renderer.Draw("You survived... for now", screen.Bounds().Dx()/2, screen.Bounds().Dy()/4)
+	s.textRenderer.Renderer.Draw(tr("win.title"), screen.Bounds().Dx()/2, screen.Bounds().Dy()/4)
 
 	timePlayed := s.Stat.GameWon.Sub(s.Stat.GameStarted).Seconds()
-	statText := fmt.Sprintf("You played %d min %d sec\n", int(timePlayed/60), int(timePlayed)%60)
-	statText = statText + fmt.Sprintf("You died %d times\n", s.Stat.CounterPlayerDied)
-	statText = statText + fmt.Sprintf("Rover died %d times\n", s.Stat.CounterDogDied)
-	statText = statText + fmt.Sprintf("You fired %d bullets\n", s.Stat.CounterBulletsFired)
-	statText = statText + fmt.Sprintf("You killed %d zombies\n", s.Stat.CounterZombiesKilled)
+	statText := tr("win.time", int(timePlayed/60), int(timePlayed)%60) + "\n"
+	statText = statText + tr("win.playerDeaths", s.Stat.CounterPlayerDied) + "\n"
+	statText = statText + tr("win.dogDeaths", s.Stat.CounterDogDied) + "\n"
+	statText = statText + tr("win.bullets", s.Stat.CounterBulletsFired) + "\n"
+	statText = statText + tr("win.kills", s.Stat.CounterZombiesKilled) + "\n"
+	statText = statText + tr("win.difficulty", tr("difficulty."+s.Stat.Difficulty)) + "\n"
+	if s.Stat.Assisted {
+		statText = statText + tr("win.assisted") + "\n"
+	}
 	s.statRenderer.Renderer.SetTarget(screen)
 	s.statRenderer.Renderer.Draw(statText, screen.Bounds().Dx()/2, screen.Bounds().Dy()/5*3)
 }
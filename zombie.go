@@ -31,6 +31,9 @@ var zombieSprinterSpeed float64 = 1.2
 // zombieRange is how far away the zombie sees something to attack
 var zombieRange float64 = 220
 
+// the centre of the zombie's head is 2px up from the middle
+const zombieCenterOffset float64 = 2
+
 // Types of zombies
 type ZombieType uint8
 
@@ -50,6 +53,9 @@ type Zombielike interface {
 	Type() ZombieType
 	Remove()
 	Position() *Coord
+	Depth() float64
+	Chasing(*GameScreen) bool
+	Corpse() *Corpse
 }
 
 // Zombies is an array of Zombie
@@ -63,6 +69,7 @@ func (zs *Zombies) Update(g *GameScreen) {
 		if err != nil {
 			// clear and remove dead zombies
 			log.Println(err)
+			g.Corpses.Add(z.Corpse())
 			g.Zombies[i] = nil
 			g.Zombies = append((*zs)[:i], (*zs)[i+1:]...)
 			z.Remove()
@@ -117,6 +124,7 @@ func NewZombie(spawnpoint *SpawnPoint, position Coord, zombieType ZombieType, sp
 
 	z := &Zombie{
 		Object:     object,
+		Body:       newMobBody(float64(dimensions.W), float64(dimensions.H)),
 		Angle:      0,
 		Sprite:     sprites,
 		Speed:      speed * (1 + rand.Float64()),
@@ -125,15 +133,19 @@ func NewZombie(spawnpoint *SpawnPoint, position Coord, zombieType ZombieType, sp
 		TempSpeed:  1,
 	}
 	z.Object.Data = z
+	z.Body.Data = z
+	centerObject(z.Body, z.Object.Position)
 	z.SpawnPoint = spawnpoint
 
 	return z
 }
 
-// Draw draws all the zombies
-func (zs Zombies) Draw(g *GameScreen) {
+// Queue adds all the zombies the player can see to the draw queue
+func (zs Zombies) Queue(g *GameScreen) {
 	for _, z := range zs {
-		z.Draw(g)
+		if g.Vision.CanSee(*z.Position()) {
+			g.DrawQueue.Add(z)
+		}
 	}
 }
 
@@ -149,6 +161,7 @@ const (
 // Zombie is a monster that's trying to eat the player character
 type Zombie struct {
 	Object     *resolv.Object // Used for collision detection with other objects
+	Body       *resolv.Object // Covers the whole sprite, used for getting shot
 	Angle      float64        // The angle the zombies is facing at
 	Frame      int            // The current animation frame
 	State      int            // The current animation state
@@ -159,6 +172,7 @@ type Zombie struct {
 	HitToDie   int            // Number of hits needed to die
 	ZombieType ZombieType     // Type of the zombie
 	SpawnPoint *SpawnPoint    // Reference for the SpawnPoint where the zombie was spawned
+	Terrain    *Terrain       // The terrain the zombie is walking on
 }
 
 // Remove the zombie from the game's list of zombies and from the spawn point's
@@ -167,9 +181,33 @@ func (z *Zombie) Remove() {
 	if z.Object.Space != nil {
 		z.Object.Space.Remove(z.Object)
 	}
+	if z.Body.Space != nil {
+		z.Body.Space.Remove(z.Body)
+	}
 	z.SpawnPoint.RemoveZombie(z)
 }
 
+// newMobBody creates an object covering a mob's whole sprite, so bullets hit
+// anywhere on it and not just the small box used for bumping into things
+func newMobBody(w, h float64) *resolv.Object {
+	body := resolv.NewObject(0, 0, w, h, tagMobBody)
+	body.SetShape(resolv.NewRectangle(0, 0, w, h))
+	return body
+}
+
+// centerObject moves an object so it's centred on the position, its top-left
+// corner is its position so that it's in all the cells of the space it covers
+func centerObject(o *resolv.Object, center resolv.Vector) {
+	o.Position.X = center.X - o.Size.X/2
+	o.Position.Y = center.Y - o.Size.Y/2
+	o.Update()
+}
+
+// Depth returns where the zombie's feet are for sorting the draw order
+func (z *Zombie) Depth() float64 {
+	return z.Object.Position.Y + z.Object.Size.Y/2
+}
+
 // Position returns the current coordinates of the zombie
 func (z *Zombie) Position() *Coord {
 	return &Coord{
@@ -183,6 +221,12 @@ func (z *Zombie) Type() ZombieType {
 	return z.ZombieType
 }
 
+// Chasing returns whether the zombie is walking after the player or the dog
+func (z *Zombie) Chasing(g *GameScreen) bool {
+	return z.State == zombieWalking &&
+		(z.Target == &g.Player.Object.Position || z.Target == &g.Dog.Object.Position)
+}
+
 // Update updates the state of the zombie
 func (z *Zombie) Update(g *GameScreen) error {
 	if z.State == zombieDead {
@@ -215,6 +259,7 @@ func (z *Zombie) Update(g *GameScreen) error {
 				}
 			}
 			z.walk()
+			z.Terrain.KickUp(g, *z.Position(), z.Angle)
 		} else {
 			z.State = zombieIdle
 		}
@@ -227,6 +272,7 @@ func (z *Zombie) Update(g *GameScreen) error {
 
 	z.Object.Shape.SetRotation(-z.Angle)
 	z.Object.Update()
+	centerObject(z.Body, z.Object.Position)
 	return nil
 }
 
@@ -289,40 +335,25 @@ func (z *Zombie) move(dx, dy float64) {
 		z.Object.Position.X += dx
 		z.Object.Position.Y += dy
 	}
-	// Collision detection and response between sand trap and zombie
-	z.TempSpeed = 1
-	if collision := z.Object.Check(0, 0, tagSandTrap); collision != nil {
-		if z.Object.Overlaps(collision.Objects[0]) {
-			if z.Object.Shape.Intersection(0, 0, collision.Objects[0].Shape) != nil {
-				z.TempSpeed = sandTrapSpeedMultiplier
-			}
-		}
-	}
+	// Terrain like sand traps slows the zombie down
+	z.Terrain = TerrainUnder(z.Object, entityZombie)
+	z.TempSpeed = z.Terrain.SpeedFor(entityZombie)
 }
 
 // Draw draws the Zombie to the screen
 func (z *Zombie) Draw(g *GameScreen) {
-	// -2, // the centre of the zombie's head is 2px up from the middle
-	const centerOffset float64 = 2
-
-	s := z.Sprite
-	frame := s.Sprite[z.Frame]
+	frame := z.Sprite.Sprite[z.Frame]
 	op := &ebiten.DrawImageOptions{}
 
 	// Centre and rotate
 	op.GeoM.Translate(
 		float64(-frame.Position.W/2),
-		float64(-frame.Position.H/2)+centerOffset/2,
+		float64(-frame.Position.H/2)+zombieCenterOffset/2,
 	)
 	op.GeoM.Rotate(z.Angle + math.Pi/2)
 
 	g.Camera.Surface.DrawImage(
-		s.Image.SubImage(image.Rect(
-			frame.Position.X,
-			frame.Position.Y,
-			frame.Position.X+frame.Position.W,
-			frame.Position.Y+frame.Position.H,
-		)).(*ebiten.Image),
+		z.frameImage(),
 		g.Camera.GetTranslation(
 			op,
 			float64(z.Object.Position.X),
@@ -332,10 +363,35 @@ func (z *Zombie) Draw(g *GameScreen) {
 
 }
 
+// frameImage returns the image of the zombie's current animation frame
+func (z *Zombie) frameImage() *ebiten.Image {
+	frame := z.Sprite.Sprite[z.Frame]
+	return z.Sprite.Image.SubImage(image.Rect(
+		frame.Position.X,
+		frame.Position.Y,
+		frame.Position.X+frame.Position.W,
+		frame.Position.Y+frame.Position.H,
+	)).(*ebiten.Image)
+}
+
+// Corpse returns what remains of the zombie to be left lying around after it
+// finished dying
+func (z *Zombie) Corpse() *Corpse {
+	return &Corpse{
+		Image:    z.frameImage(),
+		Position: *z.Position(),
+		Angle:    z.Angle,
+	}
+}
+
 // Hit changes zombie state and updates game data in response to it getting shot
 func (z *Zombie) Hit(g *GameScreen) {
 	g.Stat.CounterZombiesHit++
+	g.Particles.EmitBlood(*z.Position(), g.Player.Angle, 6)
 	z.State = zombieHit
+	if z.ZombieType == zombieBig {
+		g.CameraEffects.Stop(bossHitStopTime)
+	}
 	z.HitToDie--
 	if z.HitToDie == 0 {
 		z.Die(g)
@@ -349,6 +405,7 @@ func (z *Zombie) Hit(g *GameScreen) {
 func (z *Zombie) Die(g *GameScreen) {
 	g.Stat.CounterZombiesKilled++
 	g.Sounds[soundZombieDeath].Play()
+	g.Particles.EmitBlood(*z.Position(), g.Player.Angle, 16)
 	z.Remove()
 	z.State = zombieDeath
 }
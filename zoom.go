@@ -30,6 +30,14 @@ func NewZoom() *Zoom {
 
 // Update updates the camera with the current zoom level
 func (zoom *Zoom) Update() {
+	// Jump straight to the zoom level without animating for reduced motion
+	if reducedMotion {
+		zoom.Amount = zoomOutLevel
+		if zoom.On {
+			zoom.Amount = zoomInLevel
+		}
+		return
+	}
 	if zoom.On {
 		zoom.tweenOut.Reset()
 		amount, _ := zoom.tweenIn.Update(1)